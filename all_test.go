@@ -1,16 +1,29 @@
 package opengraph
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
+	"image"
+	"image/png"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 
+	"github.com/andybalholm/brotli"
 	"github.com/otiai10/marmoset"
 	. "github.com/otiai10/mint"
+	"golang.org/x/net/html"
+	"golang.org/x/text/encoding/unicode"
 )
 
 func TestNew(t *testing.T) {
@@ -40,7 +53,7 @@ func TestFetch(t *testing.T) {
 
 	Expect(t, err).ToBe(nil)
 	Expect(t, og.Title).ToBe("Hello! Open Graph!!")
-	Expect(t, og.Type).ToBe("website")
+	Expect(t, og.Type).ToBe(Type("website"))
 	Expect(t, og.URL.Source).ToBe(s.URL)
 	Expect(t, len(og.Image)).ToBe(1)
 
@@ -51,6 +64,164 @@ func TestFetch(t *testing.T) {
 	Expect(t, og.Favicon).ToBe(s.URL + "/images/01.favicon.png")
 }
 
+func TestOpenGraph_ToAbsURL_OGURL(t *testing.T) {
+	og := New("https://example.com/posts/1")
+	og.Policy.CollectWarnings = true
+	og.URL.Value = "/posts/1"
+	og.ToAbsURL()
+	Expect(t, og.URL.Value).ToBe("https://example.com/posts/1")
+	Expect(t, len(og.Warnings)).ToBe(1)
+	Expect(t, og.Warnings[0].Property).ToBe("og:url")
+
+	og = New("https://example.com/posts/1")
+	og.Policy.CollectWarnings = true
+	og.URL.Value = "https://example.org/posts/1"
+	og.ToAbsURL()
+	Expect(t, og.URL.Value).ToBe("https://example.org/posts/1")
+	Expect(t, len(og.Warnings)).ToBe(0)
+}
+
+func TestOpenGraph_ToAbsURL_StripTrackingParams(t *testing.T) {
+	og := New("https://example.com/")
+	og.URL.Value = "https://example.com/posts/1?id=1&utm_source=twitter&fbclid=abc"
+	og.CanonicalURL = "https://example.com/posts/1?gclid=abc"
+	og.ToAbsURL()
+	Expect(t, og.URL.Value).ToBe("https://example.com/posts/1?id=1&utm_source=twitter&fbclid=abc")
+
+	og.Policy.StripTrackingParams = true
+	og.ToAbsURL()
+	Expect(t, og.URL.Value).ToBe("https://example.com/posts/1?id=1")
+	Expect(t, og.CanonicalURL).ToBe("https://example.com/posts/1")
+
+	og.Policy.TrackingParams = []string{"id"}
+	og.URL.Value = "https://example.com/posts/1?id=1&utm_source=twitter"
+	og.ToAbsURL()
+	Expect(t, og.URL.Value).ToBe("https://example.com/posts/1?utm_source=twitter")
+
+	When(t, "the rest of the query is not canonical", func(t *testing.T) {
+		og := New("https://example.com/")
+		og.Policy.StripTrackingParams = true
+		og.URL.Value = "https://example.com/search?q=a+b&utm_medium=social&page=2&sort=%7Enew&flag"
+		og.ToAbsURL()
+		Expect(t, og.URL.Value).ToBe("https://example.com/search?q=a+b&page=2&sort=%7Enew&flag")
+	})
+}
+
+func TestFetchJSON(t *testing.T) {
+	s := dummyServer(1)
+	defer s.Close()
+	b, err := FetchJSON(context.Background(), s.URL)
+	Expect(t, err).ToBe(nil)
+	og := new(OpenGraph)
+	Expect(t, json.Unmarshal(b, og)).ToBe(nil)
+	Expect(t, og.Title).ToBe("Hello! Open Graph!!")
+	Expect(t, og.Image[0].URL).ToBe(s.URL + "/images/01.png")
+
+	_, err = FetchJSON(context.Background(), s.URL+"/not-html.png")
+	Expect(t, err).Not().ToBe(nil)
+}
+
+func TestOpenGraph_ToAbsURL_URLRewriter(t *testing.T) {
+	og := New("https://example.com/posts/")
+	og.Image = []*OGImage{{URL: "/a.png", SURL: "/secure/a.png"}}
+	og.Video = []*OGVideo{{URL: "https://cdn.example.com/a.mp4"}}
+	og.Audio = []*OGAudio{{URL: "/a.mp3"}}
+	og.URL.Value = "https://example.com/posts/1"
+	og.CanonicalURL = "/posts/1"
+	og.Policy.URLRewriter = func(u string) string {
+		return "https://proxy.example.net/?url=" + url.QueryEscape(u)
+	}
+	og.ToAbsURL()
+	Expect(t, og.Image[0].URL).ToBe("https://proxy.example.net/?url=https%3A%2F%2Fexample.com%2Fa.png")
+	Expect(t, og.Image[0].SURL).ToBe("https://proxy.example.net/?url=https%3A%2F%2Fexample.com%2Fsecure%2Fa.png")
+	Expect(t, og.Video[0].URL).ToBe("https://proxy.example.net/?url=https%3A%2F%2Fcdn.example.com%2Fa.mp4")
+	Expect(t, og.Video[0].SURL).ToBe("")
+	Expect(t, og.Audio[0].URL).ToBe("https://proxy.example.net/?url=https%3A%2F%2Fexample.com%2Fa.mp3")
+	Expect(t, og.Favicon).ToBe("https://proxy.example.net/?url=https%3A%2F%2Fexample.com%2Ffavicon.ico")
+	Expect(t, og.URL.Value).ToBe("https://example.com/posts/1")
+	Expect(t, og.CanonicalURL).ToBe("https://example.com/posts/1")
+
+	When(t, "ToAbsURL is called again", func(t *testing.T) {
+		og.ToAbsURL()
+		Expect(t, og.Image[0].URL).ToBe("https://proxy.example.net/?url=https%3A%2F%2Fexample.com%2Fa.png")
+		Expect(t, og.Favicon).ToBe("https://proxy.example.net/?url=https%3A%2F%2Fexample.com%2Ffavicon.ico")
+	})
+}
+
+func TestOpenGraph_ToAbsURL_UpgradeInsecureURLs(t *testing.T) {
+	og := New("https://example.com/")
+	og.Policy.CollectWarnings = true
+	og.Policy.UpgradeInsecureURLs = true
+	og.Favicon = "http://example.com/favicon.png"
+	og.Image = []*OGImage{
+		{URL: "http://example.com/a.png"},
+		{URL: "http://example.com/b.png", SURL: "https://secure.example.com/b.png"},
+	}
+	og.ToAbsURL()
+	Expect(t, og.Favicon).ToBe("https://example.com/favicon.png")
+	Expect(t, og.Image[0].URL).ToBe("https://example.com/a.png")
+	Expect(t, og.Image[1].URL).ToBe("http://example.com/b.png")
+	Expect(t, len(og.Warnings)).ToBe(2)
+
+	og = New("http://example.com/")
+	og.Policy.UpgradeInsecureURLs = true
+	og.Image = []*OGImage{{URL: "http://example.com/a.png"}}
+	og.ToAbsURL()
+	Expect(t, og.Image[0].URL).ToBe("http://example.com/a.png")
+}
+
+func TestOpenGraph_Identity(t *testing.T) {
+	og := New("https://example.com/posts/1?utm_source=feed")
+	Expect(t, og.Identity()).ToBe("https://example.com/posts/1")
+
+	og.URL.Value = "HTTPS://Example.COM/posts/1?b=2&a=1&fbclid=x#comments"
+	Expect(t, og.Identity()).ToBe("https://example.com/posts/1?a=1&b=2")
+
+	og.CanonicalURL = "/articles/1"
+	Expect(t, og.Identity()).ToBe("https://example.com/articles/1")
+
+	og = New("https://example.com/short")
+	og.RedirectChain = []string{"https://example.com/short", "https://www.example.com/posts/1#top"}
+	Expect(t, og.Identity()).ToBe("https://www.example.com/posts/1")
+
+	Expect(t, (&OpenGraph{}).Identity()).ToBe("")
+}
+
+func TestOpenGraph_SlackUnfurl(t *testing.T) {
+	doc := `<meta property="og:title" content="Title">
+	<meta property="og:description" content="Description">
+	<meta property="og:url" content="/posts/1">
+	<meta property="og:image" content="http://example.com/insecure.png">
+	<meta property="og:image" content="http://example.com/secure.png">
+	<meta property="og:image:secure_url" content="/secure.png">
+	<link rel="icon" href="/icon.png">`
+	og := New("https://example.com/")
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.SlackUnfurl()).ToBe(SlackUnfurl{
+		Title:     "Title",
+		TitleLink: "https://example.com/posts/1",
+		Text:      "Description",
+		ImageURL:  "https://example.com/secure.png",
+		ThumbURL:  "https://example.com/icon.png",
+	})
+
+	og = New("http://example.com/posts/2")
+	Expect(t, og.Parse(strings.NewReader(`<meta property="og:image" content="/image.png">`))).ToBe(nil)
+	unfurl := og.SlackUnfurl()
+	Expect(t, unfurl.TitleLink).ToBe("http://example.com/posts/2")
+	Expect(t, unfurl.ImageURL).ToBe("")
+	Expect(t, unfurl.ThumbURL).ToBe("")
+}
+
+func TestOpenGraph_AbsoluteImageURLs(t *testing.T) {
+	og := New("https://example.com/posts/1")
+	og.Image = append(og.Image, &OGImage{URL: "/1.png"}, &OGImage{URL: "https://cdn.example.com/2.png"})
+	og.Video = append(og.Video, &OGVideo{URL: "/1.mp4"})
+	Expect(t, og.AbsoluteImageURLs()).ToBe([]string{"https://example.com/1.png", "https://cdn.example.com/2.png"})
+	Expect(t, og.AbsoluteVideoURLs()).ToBe([]string{"https://example.com/1.mp4"})
+	Expect(t, og.Image[0].URL).ToBe("/1.png")
+}
+
 func TestFetch_02(t *testing.T) {
 	s := dummyServer(2)
 	og, err := Fetch(s.URL)
@@ -60,6 +231,7 @@ func TestFetch_02(t *testing.T) {
 	Expect(t, og.Description).ToBe("All Genre Music Party")
 	Expect(t, og.URL.Value).ToBe("https://haisai.party/")
 	Expect(t, og.CanonicalURL).ToBe("https://open.spotify.com/")
+	Expect(t, og.Feeds).ToBe([]Feed{{Title: "はいさいナイト", Href: "https://haisai.party/index.xml", Type: "application/rss+xml"}})
 
 	// b := bytes.NewBuffer(nil)
 	// json.NewEncoder(b).Encode(og)
@@ -101,31 +273,2526 @@ func TestFetch_05_TrustedTags(t *testing.T) {
 	Expect(t, og.Title).ToBe("")
 }
 
-func TestFetchWithContext(t *testing.T) {
-	s := dummySlowServer(time.Millisecond * 300)
-	defer s.Close()
+func TestParse_Facebook(t *testing.T) {
+	og := New("https://example.com/")
+	err := og.Parse(strings.NewReader(`<html><head>
+	<meta property="fb:app_id" content="1234567890">
+	<meta property="fb:admins" content="111, 222">
+	<meta property="fb:admins" content="333">
+	</head></html>`))
+	Expect(t, err).ToBe(nil)
+	Expect(t, og.Facebook.AppID).ToBe("1234567890")
+	Expect(t, og.Facebook.Admins).ToBe([]string{"111", "222", "333"})
+}
 
-	ctx500ms, cancel500ms := context.WithTimeout(context.Background(), time.Millisecond*500)
-	_, err := FetchWithContext(ctx500ms, s.URL)
+func TestParse_WarnIncompleteImages(t *testing.T) {
+	doc := `<html><head>
+	<meta property="og:image" content="/1.png">
+	<meta property="og:image:width" content="400">
+	<meta property="og:image" content="/2.png">
+	<meta property="og:image:width" content="400">
+	<meta property="og:image:height" content="300">
+	<meta property="og:image:type" content="image/png">
+	</head></html>`
+	og := New("https://example.com/")
+	err := og.Parse(strings.NewReader(doc))
 	Expect(t, err).ToBe(nil)
-	cancel500ms()
+	Expect(t, len(og.Warnings)).ToBe(0)
 
-	ctx100ms, cancel100ms := context.WithTimeout(context.Background(), time.Millisecond*100)
-	_, err = FetchWithContext(ctx100ms, s.URL)
-	Expect(t, err).Match(context.DeadlineExceeded.Error())
-	cancel100ms()
+	When(t, "CollectWarnings is set", func(t *testing.T) {
+		og := New("https://example.com/")
+		og.Policy.CollectWarnings = true
+		err := og.Parse(strings.NewReader(doc))
+		Expect(t, err).ToBe(nil)
+		Expect(t, len(og.Warnings)).ToBe(1)
+		Expect(t, og.Warnings[0].String()).ToBe("og:image: /1.png lacks [og:image:height og:image:type]")
+		Expect(t, og.Image[1].Type).ToBe("image/png")
+	})
 }
 
-func dummyServer(id int) *httptest.Server {
-	marmoset.LoadViews("./test/html")
-	r := marmoset.NewRouter()
-	r.GET("/", func(w http.ResponseWriter, r *http.Request) {
-		marmoset.Render(w).HTML(fmt.Sprintf("%02d", id), nil)
+func TestParse_MultipleURL(t *testing.T) {
+	doc := `<html><head>
+	<meta property="og:url" content="https://cdn.example.net/a">
+	<meta property="og:url" content="https://example.com/a">
+	<meta property="og:url" content="https://example.org/a">
+	</head></html>`
+	og := New("https://example.com/a?ref=1")
+	og.Policy.CollectWarnings = true
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.URL.Value).ToBe("https://example.org/a")
+	Expect(t, len(og.Warnings)).ToBe(1)
+	Expect(t, og.Warnings[0].Property).ToBe("og:url")
+
+	og = New("https://example.com/a?ref=1")
+	og.Policy.URLPolicy = URLPolicyFirst
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.URL.Value).ToBe("https://cdn.example.net/a")
+
+	og = New("https://example.com/a?ref=1")
+	og.Policy.URLPolicy = URLPolicyMatchingHost
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.URL.Value).ToBe("https://example.com/a")
+}
+
+func TestParse_MaxImages(t *testing.T) {
+	og := New("https://example.com/")
+	og.Policy.MaxImages = 2
+	og.Policy.MaxVideos = 1
+	og.Policy.CollectWarnings = true
+	err := og.Parse(strings.NewReader(`<html><head>
+	<meta property="og:image" content="/1.png">
+	<meta property="og:image:width" content="100">
+	<meta property="og:image" content="/2.png">
+	<meta property="og:image:width" content="200">
+	<meta property="og:image" content="/3.png">
+	<meta property="og:image:width" content="300">
+	<meta property="og:image" content="/4.png">
+	<meta property="og:video" content="/1.mp4">
+	<meta property="og:video:type" content="video/mp4">
+	<meta property="og:video" content="/2.mp4">
+	<meta property="og:audio" content="/1.mp3">
+	<meta property="og:audio" content="/2.mp3">
+	</head></html>`))
+	Expect(t, err).ToBe(nil)
+	Expect(t, len(og.Image)).ToBe(2)
+	Expect(t, og.Image[1].URL).ToBe("/2.png")
+	Expect(t, og.Image[1].Width).ToBe(200)
+	Expect(t, len(og.Video)).ToBe(1)
+	Expect(t, og.Video[0].Type).ToBe("video/mp4")
+	Expect(t, len(og.Audio)).ToBe(2)
+	Expect(t, og.Warnings[0].String()).ToBe("og:image: exceeds max 2, the rest are dropped")
+	Expect(t, og.Warnings[1].String()).ToBe("og:video: exceeds max 1, the rest are dropped")
+}
+
+func TestParse_ThemeColor(t *testing.T) {
+	doc := `<html><head>
+	<title>Document</title>
+	<meta name="theme-color" media="(prefers-color-scheme: dark)" content="#000000">
+	<meta name="theme-color" content="#1da1f2">
+	<meta name="theme-color" content="#ffffff">
+	</head></html>`
+	og := New("https://example.com/")
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.ThemeColor).ToBe("#1da1f2")
+	Expect(t, og.Title).ToBe("Document")
+
+	When(t, "Strict is set", func(t *testing.T) {
+		og := New("https://example.com/")
+		og.Policy.Strict = true
+		Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+		Expect(t, og.ThemeColor).ToBe("")
+		Expect(t, og.Title).ToBe("")
 	})
-	r.GET("/case/01", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusNotFound)
+}
+
+func TestParse_StopAtBody(t *testing.T) {
+	doc := `<html><head>
+	<meta property="og:title" content="Head">
+	</head><body>
+	<meta property="og:description" content="Body">
+	</body></html>`
+	og := New("https://example.com/")
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Description).ToBe("Body")
+
+	og = New("https://example.com/")
+	og.Policy.StopAtBody = true
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Title).ToBe("Head")
+	Expect(t, og.Description).ToBe("")
+}
+
+func TestParse_PreferHead(t *testing.T) {
+	doc := `<html><head>
+	<meta property="og:title" content="Theme">
+	</head><body>
+	<meta property="og:title" content="Plugin">
+	<meta property="og:description" content="Plugin">
+	<meta property="og:description" content="Plugin again">
+	</body></html>`
+	og := New("https://example.com/")
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Title).ToBe("Plugin")
+
+	og = New("https://example.com/")
+	og.Policy.PreferHead = true
+	og.Policy.CollectWarnings = true
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Title).ToBe("Theme")
+	Expect(t, og.Description).ToBe("Plugin again")
+	Expect(t, og.Warnings[0]).ToBe(Warning{
+		Property: "og:title",
+		Message:  `declared twice: "Theme" and "Plugin"`,
+		First:    "Theme",
+		Second:   "Plugin",
+		FirstIn:  "head",
+		SecondIn: "body",
 	})
-	return httptest.NewServer(r)
+	Expect(t, og.Warnings[1].FirstIn).ToBe("body")
+	Expect(t, og.Warnings[1].SecondIn).ToBe("body")
+}
+
+func TestParse_MaxNodes(t *testing.T) {
+	// Document, <html>, <head>, two <meta> and <body>.
+	doc := `<meta property="og:title" content="Title"><meta property="og:description" content="Description">`
+	og := New("https://example.com/")
+	og.Policy.MaxNodes = 4
+	og.Policy.CollectWarnings = true
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Title).ToBe("Title")
+	Expect(t, og.Description).ToBe("")
+	Expect(t, len(og.Warnings)).ToBe(1)
+	Expect(t, og.Warnings[0].Property).ToBe("html")
+
+	og = New("https://example.com/")
+	og.Policy.MaxNodes = 6
+	og.Policy.CollectWarnings = true
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Description).ToBe("Description")
+	Expect(t, len(og.Warnings)).ToBe(0)
+}
+
+func TestParse_CollectLinks(t *testing.T) {
+	doc := `<html><head></head><body><ul>
+	<li><a href="https://example.org/">Other</a></li>
+	<li><a href="/about">About</a></li>
+	<li><a href="https://example.org/">Dup</a></li>
+	<li><a href="javascript:void(0)">JS</a></li>
+	<li><a href="mailto:me@example.com">Mail</a></li>
+	<li><a href="#top">Top</a></li>
+	</ul></body></html>`
+	og := New("https://example.com/")
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, len(og.Links)).ToBe(0)
+
+	og = New("https://example.com/")
+	og.Policy.CollectLinks = true
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Links).ToBe([]string{"https://example.org/", "https://example.com/about"})
+}
+
+func TestParse_MicrodataFallback(t *testing.T) {
+	doc := `<html><head>
+	<meta name="description" content="Meta Description">
+	</head><body itemscope itemtype="https://schema.org/Product">
+	<h1 itemprop="name">Microdata Name</h1>
+	<p itemprop="description">Microdata Description</p>
+	<img itemprop="image" src="images/1.png">
+	</body></html>`
+	og := New("https://example.com/products/")
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Title).ToBe("")
+	Expect(t, len(og.Image)).ToBe(0)
+
+	og = New("https://example.com/products/")
+	og.Policy.MicrodataFallback = true
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Title).ToBe("Microdata Name")
+	Expect(t, og.Description).ToBe("Meta Description")
+	Expect(t, og.Image[0].URL).ToBe("https://example.com/products/images/1.png")
+	Expect(t, og.Provenance).ToBe(map[string]string{"Title": "microdata", "Image": "microdata", "SiteName": "host"})
+
+	og = New("https://example.com/products/")
+	og.Policy.MicrodataFallback = true
+	og.Policy.Strict = true
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Title).ToBe("")
+}
+
+func TestParse_ItempropAndProperty(t *testing.T) {
+	doc := `<meta itemprop="image" property="og:image" content="https://tracker.example.net/pixel.gif">
+	<meta itemprop="name" property="og:title" content="OGP">
+	<meta itemprop="description" content="Microdata">`
+	og := New("https://example.com/")
+	og.Policy.MicrodataFallback = true
+	og.Policy.AllowedImageHosts = []string{"example.com"}
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Title).ToBe("OGP")
+	Expect(t, og.Provenance["Title"]).ToBe("")
+	Expect(t, len(og.Image)).ToBe(0)
+	Expect(t, og.Description).ToBe("Microdata")
+	Expect(t, og.Provenance["Description"]).ToBe(ProvenanceMicrodata)
+
+	og = New("https://example.com/")
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Description).ToBe("")
+}
+
+func TestParse_DataURIImage(t *testing.T) {
+	doc := `<html><head>
+	<meta property="og:image" content="/1.png">
+	<meta property="og:image:width" content="100">
+	<meta property="og:image" content="data:image/png;base64,iVBORw0KGgo=">
+	<meta property="og:image:width" content="1">
+	</head></html>`
+	og := New("https://example.com/")
+	og.Policy.CollectWarnings = true
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, len(og.Image)).ToBe(1)
+	Expect(t, og.Image[0].Width).ToBe(100)
+	Expect(t, og.Warnings[0].String()).ToBe("og:image: data URI is rejected")
+
+	og = New("https://example.com/")
+	og.Policy.AllowDataURIImages = true
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, len(og.Image)).ToBe(2)
+	Expect(t, og.Image[1].Width).ToBe(1)
+}
+
+func TestParse_Restrictions(t *testing.T) {
+	og := New("https://example.com/")
+	Expect(t, og.Parse(strings.NewReader(`<html><head>
+	<meta property="og:image" content="/1.png">
+	<meta property="og:image:user_generated" content="true">
+	<meta property="og:restrictions:age" content="18+">
+	<meta property="og:restrictions:country:allowed" content="US">
+	<meta property="og:restrictions:country:allowed" content="CA">
+	<meta property="og:restrictions:country:disallowed" content="JP">
+	</head></html>`))).ToBe(nil)
+	Expect(t, og.Image[0].UserGenerated).ToBe(true)
+	Expect(t, og.Restrictions.Age).ToBe("18+")
+	Expect(t, og.Restrictions.CountryAllowed).ToBe([]string{"US", "CA"})
+	Expect(t, og.Restrictions.CountryDisallowed).ToBe([]string{"JP"})
+
+	og = New("https://example.com/")
+	Expect(t, og.Parse(strings.NewReader(`<html></html>`))).ToBe(nil)
+	Expect(t, og.Restrictions).ToBe((*OGRestrictions)(nil))
+}
+
+func TestParse_FirstWins(t *testing.T) {
+	doc := `<html><head>
+	<title>Document</title>
+	<meta property="og:title" content="First">
+	<meta property="og:title" content="Second">
+	<meta property="og:url" content="https://example.com/first">
+	<meta property="og:url" content="https://example.com/second">
+	<meta property="og:image" content="/1.png">
+	<meta property="og:image" content="/2.png">
+	</head></html>`
+	og := New("https://example.com/")
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Title).ToBe("Second")
+	Expect(t, og.URL.Value).ToBe("https://example.com/second")
+
+	og = New("https://example.com/")
+	og.Policy.FirstWins = true
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Title).ToBe("First")
+	Expect(t, og.URL.Value).ToBe("https://example.com/first")
+	Expect(t, len(og.Image)).ToBe(2)
+}
+
+func TestParse_ResolveConflict(t *testing.T) {
+	doc := `<meta property="og:title" content="A long title">
+	<meta property="og:title" content="Short">
+	<meta property="og:url" content="https://example.com/posts/1">
+	<meta property="og:url" content="https://cdn.example.net/posts/1">
+	<meta property="og:description" content="Only">`
+	calls := []string{}
+	og := New("https://example.com/")
+	og.Policy.ResolveConflict = func(property, existing, incoming string) string {
+		calls = append(calls, property)
+		if len(incoming) > len(existing) {
+			return incoming
+		}
+		return existing
+	}
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Title).ToBe("A long title")
+	Expect(t, og.URL.Value).ToBe("https://cdn.example.net/posts/1")
+	Expect(t, og.Description).ToBe("Only")
+	Expect(t, calls).ToBe([]string{"og:title", "og:url"})
+}
+
+func TestParse_MultiTokenRel(t *testing.T) {
+	og := New("https://example.com/")
+	err := og.Parse(strings.NewReader(`<html><head>
+	<link rel="icon shortcut" href="/favicon.png">
+	<link rel="canonical alternate" href="https://example.com/canonical">
+	<link rel=" Alternate  feed " type="application/rss+xml" href="/index.xml">
+	<link rel="apple-touch-icon" href="/apple.png">
+	</head></html>`))
+	Expect(t, err).ToBe(nil)
+	Expect(t, og.Favicon).ToBe("/favicon.png")
+	Expect(t, og.CanonicalURL).ToBe("https://example.com/canonical")
+	Expect(t, og.Feeds).ToBe([]Feed{{Href: "https://example.com/index.xml", Type: "application/rss+xml"}})
+
+	Expect(t, (&Link{Rel: "shortcut icon"}).IsFavicon()).ToBe(true)
+	Expect(t, (&Link{Rel: "mask-icon"}).IsFavicon()).ToBe(false)
+}
+
+func TestParse_DisableFallbacks(t *testing.T) {
+	doc := `<html><head>
+	<title>Document</title>
+	<meta property="og:title" content="">
+	<link rel="canonical" href="https://example.com/canonical">
+	</head></html>`
+	og := New("https://example.com/")
+	og.Policy.DisableTitleFallback = true
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Title).ToBe("")
+	Expect(t, og.CanonicalURL).ToBe("https://example.com/canonical")
+
+	og = New("https://example.com/")
+	og.Policy.DisableLinkFallback = true
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Title).ToBe("Document")
+	Expect(t, og.CanonicalURL).ToBe("")
+}
+
+func TestParse_VideoObject(t *testing.T) {
+	doc := `<html><head>
+	<meta property="video:actor" content="https://example.com/actors/1">
+	<meta property="video:actor:role" content="Hero">
+	<meta property="video:actor" content="https://example.com/actors/2">
+	<meta property="video:director" content="https://example.com/directors/1">
+	<meta property="video:duration" content="7200">
+	<meta property="video:release_date" content="2020-10-10">
+	<meta property="og:type" content="%s">
+	</head></html>`
+	og := New("https://example.com/")
+	Expect(t, og.Parse(strings.NewReader(fmt.Sprintf(doc, "video.movie")))).ToBe(nil)
+	Expect(t, og.VideoObject.Actors).ToBe([]OGActor{
+		{Profile: "https://example.com/actors/1", Role: "Hero"},
+		{Profile: "https://example.com/actors/2"},
+	})
+	Expect(t, og.VideoObject.Directors).ToBe([]string{"https://example.com/directors/1"})
+	Expect(t, og.VideoObject.Duration).ToBe(7200)
+	Expect(t, og.VideoObject.ReleaseDate.Year()).ToBe(2020)
+
+	og = New("https://example.com/")
+	Expect(t, og.Parse(strings.NewReader(fmt.Sprintf(doc, "website")))).ToBe(nil)
+	Expect(t, og.VideoObject).ToBe((*OGVideoObject)(nil))
+}
+
+func TestParse_RecoverCommentedMeta(t *testing.T) {
+	doc := `<html><head>
+	<!-- <meta property="og:title" content="Commented"> -->
+	<!-- <meta property="og:image" content="/1.png"><meta property="og:image:width" content="100"> -->
+	<!-- not a <meta -->
+	</head><body>
+	<![CDATA[<meta property="og:description" content="CDATA">]]>
+	</body></html>`
+	og := New("https://example.com/")
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Title).ToBe("")
+	Expect(t, len(og.Image)).ToBe(0)
+
+	og = New("https://example.com/")
+	og.Policy.RecoverCommentedMeta = true
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Title).ToBe("Commented")
+	Expect(t, len(og.Image)).ToBe(1)
+	Expect(t, og.Image[0].Width).ToBe(100)
+	Expect(t, og.Description).ToBe("CDATA")
+}
+
+func TestParse_Capabilities(t *testing.T) {
+	og := New("https://example.com/")
+	Expect(t, og.Parse(strings.NewReader(`<meta property="og:title" content="Basic"><meta property="og:image" content="/1.png">`))).ToBe(nil)
+	Expect(t, og.Capabilities).ToBe(Capabilities{})
+
+	doc := `<head>
+	<meta property="og:type" content="product.item">
+	<meta property="og:image" content="/1.png">
+	<meta property="og:image:width" content="100">
+	<meta name="twitter:card" content="summary_large_image">
+	<script type="application/ld+json">{"@context": "https://schema.org"}</script>
+	</head>`
+	og = New("https://example.com/")
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Capabilities).ToBe(Capabilities{
+		HasStructuredImages: true,
+		HasVerticalType:     true,
+		HasTwitterCard:      true,
+		HasJSONLD:           true,
+	})
+}
+
+func TestParse_DocumentTitle(t *testing.T) {
+	doc := `<head><title>Document | Example</title><meta property="og:title" content="OGP"></head>`
+	og := New("https://example.com/")
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Title).ToBe("OGP")
+	Expect(t, og.DocumentTitle).ToBe("Document | Example")
+
+	og = New("https://example.com/")
+	og.Policy.Strict = true
+	Expect(t, og.Parse(strings.NewReader(`<head><title>Document</title></head>`))).ToBe(nil)
+	Expect(t, og.Title).ToBe("")
+	Expect(t, og.DocumentTitle).ToBe("Document")
+
+	og = New("https://example.com/")
+	og.Policy.TrustedTags = []string{HTMLMetaTag}
+	Expect(t, og.Parse(strings.NewReader(`<head><title>Document</title></head>`))).ToBe(nil)
+	Expect(t, og.Title).ToBe("")
+	Expect(t, og.DocumentTitle).ToBe("Document")
+}
+
+func TestParse_XHTML(t *testing.T) {
+	doc := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html PUBLIC "-//W3C//DTD XHTML+RDFa 1.0//EN" "http://www.w3.org/MarkUp/DTD/xhtml-rdfa-1.dtd">
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:og="http://ogp.me/ns#" xml:lang="en">
+<head>
+<title>XHTML</title>
+<script type="text/javascript" src="/a.js" />
+<style type="text/css"/>
+<link rel="icon" href="/icon.png" />
+<meta property="og:title" content="Title &amp; more" />
+<meta property="og:image" content="/1.png" /><meta property="og:image:width" content="100"/>
+</head>
+<body><p>body</p></body>
+</html>`
+	og := New("https://example.com/")
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Title).ToBe("Title & more")
+	Expect(t, og.DocumentTitle).ToBe("XHTML")
+	Expect(t, og.Favicon).ToBe("/icon.png")
+	Expect(t, len(og.Image)).ToBe(1)
+	Expect(t, og.Image[0].Width).ToBe(100)
+
+	When(t, "served as application/xhtml+xml", func(t *testing.T) {
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/xhtml+xml; charset=utf-8")
+			fmt.Fprint(w, `<html xmlns="http://www.w3.org/1999/xhtml"><head>
+			<script src="/a.js"/><meta property="og:title" content="Served" /></head></html>`)
+		}))
+		defer s.Close()
+		og, err := Fetch(s.URL)
+		Expect(t, err).ToBe(nil)
+		Expect(t, og.Title).ToBe("Served")
+	})
+}
+
+func TestParse_PrefixDeclaration(t *testing.T) {
+	doc := `<html prefix="ogp: http://ogp.me/ns# a: https://ogp.me/ns/article#">
+	<head xmlns:fbk="http://ogp.me/ns/fb#">
+	<meta property="ogp:title" content="Title">
+	<meta property="OGP:type" content="article">
+	<meta property="a:published_time" content="2020-01-02">
+	<meta property="fbk:app_id" content="123">
+	<meta property="og:description" content="Still og">
+	<meta property="x:title" content="Unknown">
+	</head></html>`
+	og := New("https://example.com/")
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Title).ToBe("Title")
+	Expect(t, og.Type).ToBe(Type("article"))
+	Expect(t, og.Article.PublishedTime).ToBe("2020-01-02")
+	Expect(t, og.Facebook.AppID).ToBe("123")
+	Expect(t, og.Description).ToBe("Still og")
+
+	og = New("https://example.com/")
+	Expect(t, og.Parse(strings.NewReader(`<meta property="ogp:title" content="Title">`))).ToBe(nil)
+	Expect(t, og.Title).ToBe("")
+}
+
+func TestParse_IsAMP(t *testing.T) {
+	for _, doc := range []string{
+		`<html amp><head><link rel="canonical" href="/posts/1"></head></html>`,
+		`<html ⚡ lang="en"><head><link rel="canonical" href="/posts/1"></head></html>`,
+	} {
+		og := New("https://example.com/amp/posts/1")
+		Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+		Expect(t, og.IsAMP).ToBe(true)
+		Expect(t, og.ToAbsURL().CanonicalURL).ToBe("https://example.com/posts/1")
+	}
+
+	og := New("https://example.com/posts/1")
+	Expect(t, og.Parse(strings.NewReader(`<html><head><link rel="amphtml" href="/amp/posts/1"></head></html>`))).ToBe(nil)
+	Expect(t, og.IsAMP).ToBe(false)
+}
+
+func TestOpenGraph_Authors(t *testing.T) {
+	doc := `<html><head>
+	<meta name="author" content="Jane Doe">
+	<meta property="article:author" content="John Smith">
+	<meta property="article:author" content="Jane Doe">
+	<meta name="author" content="Editorial Team">
+	</head></html>`
+	og := New("https://example.com/")
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Authors()).ToBe([]string{"John Smith", "Jane Doe", "Editorial Team"})
+
+	og = New("https://example.com/")
+	og.Policy.Strict = true
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Authors()).ToBe([]string{"John Smith", "Jane Doe"})
+}
+
+func TestParse_TrimURL(t *testing.T) {
+	doc := `<meta property="og:image" content=" 'https://example.com/a.png' ">
+	<meta property="og:image:secure_url" content="https://example.com/a.png
+">
+	<meta property="og:video" content='"https://example.com/a.mp4"'>
+	<meta property="og:url" content="  https://example.com/posts/1 ">
+	<meta property="og:title" content=" 'Quoted' ">
+	<link rel="icon" href=" /favicon.png ">`
+	og := New("https://example.com/")
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Image[0].URL).ToBe("https://example.com/a.png")
+	Expect(t, og.Image[0].SURL).ToBe("https://example.com/a.png")
+	Expect(t, og.Video[0].URL).ToBe("https://example.com/a.mp4")
+	Expect(t, og.URL.Value).ToBe("https://example.com/posts/1")
+	Expect(t, og.Favicon).ToBe("/favicon.png")
+	Expect(t, og.Title).ToBe(" 'Quoted' ")
+	Expect(t, trimURL(`"https://example.com/a.png'`)).ToBe(`"https://example.com/a.png'`)
+}
+
+func TestParse_AllowedImageHosts(t *testing.T) {
+	doc := `<meta property="og:image" content="https://tracker.example.net/pixel.gif">
+	<meta property="og:image:width" content="1">
+	<meta property="og:image" content="https://img.trusted-cdn.com/1.png">
+	<meta property="og:image:secure_url" content="https://tracker.example.net/1.png">
+	<meta property="og:image" content="/2.png">
+	<meta property="og:image" content="data:image/png;base64,iVBORw0KGgo=">`
+	og := New("https://example.com/")
+	og.Policy.AllowedImageHosts = []string{".trusted-cdn.com", "example.com"}
+	og.Policy.AllowDataURIImages = true
+	og.Policy.CollectWarnings = true
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, len(og.Image)).ToBe(2)
+	Expect(t, og.Image[0].URL).ToBe("https://img.trusted-cdn.com/1.png")
+	Expect(t, og.Image[0].SURL).ToBe("")
+	Expect(t, og.Image[1].URL).ToBe("/2.png")
+	Expect(t, og.Warnings[0].String()).ToBe(`og:image: host of "https://tracker.example.net/pixel.gif" is not allowed`)
+	Expect(t, og.Warnings[1].Property).ToBe("og:image:secure_url")
+	Expect(t, og.Warnings[2].Property).ToBe("og:image")
+
+	When(t, "the image is guessed from body", func(t *testing.T) {
+		og := New("https://example.com/")
+		og.Policy.AllowedImageHosts = []string{".trusted-cdn.com"}
+		og.Policy.GuessImageFromBody = true
+		Expect(t, og.Parse(strings.NewReader(`<body><img src="https://tracker.example.net/pixel.gif"></body>`))).ToBe(nil)
+		Expect(t, len(og.Image)).ToBe(0)
+	})
+}
+
+func TestParse_DangerousSchemes(t *testing.T) {
+	doc := `<meta property="og:url" content="javascript:alert(1)">
+	<meta property="og:image" content=" JavaScript:alert(1)">
+	<meta property="og:image:width" content="400">
+	<meta property="og:image" content="/a.png">
+	<meta property="og:image:secure_url" content="vbscript:msgbox(1)">
+	<meta property="og:video" content="ftp://example.com/a.mp4">
+	<link rel="icon" href="javascript:alert(1)">`
+	og := New("https://example.com/")
+	og.Policy.CollectWarnings = true
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.URL.Value).ToBe("")
+	Expect(t, len(og.Image)).ToBe(1)
+	Expect(t, *og.Image[0]).Deeply().ToBe(OGImage{URL: "/a.png"})
+	Expect(t, len(og.Video)).ToBe(0)
+	Expect(t, og.Favicon).ToBe("/favicon.ico")
+	Expect(t, og.Warnings[0]).ToBe(Warning{Property: "og:url", Message: `URL of scheme "javascript" is rejected`})
+
+	og = New("https://example.com/")
+	og.Policy.AllowedURLSchemes = []string{"https", "ftp"}
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Video[0].URL).ToBe("ftp://example.com/a.mp4")
+
+	When(t, "the image is guessed from body", func(t *testing.T) {
+		for _, src := range []string{"javascript:alert(1)", "data:image/png;base64,iVBORw0KGgo="} {
+			og := New("https://example.com/")
+			og.Policy.GuessImageFromBody = true
+			Expect(t, og.Parse(strings.NewReader(`<body><img src="`+src+`"></body>`))).ToBe(nil)
+			Expect(t, len(og.Image)).ToBe(0)
+		}
+	})
+
+	When(t, "a rejected image is followed by another one in body", func(t *testing.T) {
+		og := New("https://example.com/")
+		og.Policy.GuessImageFromBody = true
+		Expect(t, og.Parse(strings.NewReader(`<body><img src="data:image/gif;base64,R0lGODlhAQABAAAAACw="><img src="/real.jpg"></body>`))).ToBe(nil)
+		Expect(t, len(og.Image)).ToBe(1)
+		Expect(t, og.Image[0].URL).ToBe("https://example.com/real.jpg")
+	})
+
+	When(t, "the image is filled by microdata", func(t *testing.T) {
+		for _, src := range []string{"javascript:alert(1)", "data:image/png;base64,iVBORw0KGgo="} {
+			og := New("https://example.com/")
+			og.Policy.MicrodataFallback = true
+			Expect(t, og.Parse(strings.NewReader(`<div itemscope><link itemprop="image" href="`+src+`"></div>`))).ToBe(nil)
+			Expect(t, len(og.Image)).ToBe(0)
+		}
+	})
+
+	When(t, "a feed has a dangerous scheme", func(t *testing.T) {
+		og := New("https://example.com/")
+		Expect(t, og.Parse(strings.NewReader(`<link rel="alternate" type="application/rss+xml" href="javascript:alert(1)">`))).ToBe(nil)
+		Expect(t, len(og.Feeds)).ToBe(0)
+	})
+}
+
+func TestParse_IgnoreEmptyValues(t *testing.T) {
+	doc := `<meta property="og:type" content="article">
+	<meta property="og:type" content=" ">
+	<meta property="og:image" content="">
+	<meta property="og:image:width" content="400">`
+	og := New("https://example.com/")
+	Expect(t, og.Policy.IgnoreEmptyValues).ToBe(true)
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Type).ToBe(Type("article"))
+	Expect(t, len(og.Image)).ToBe(0)
+
+	When(t, "FirstWins", func(t *testing.T) {
+		og := New("https://example.com/")
+		og.Policy.FirstWins = true
+		Expect(t, og.Parse(strings.NewReader(`<meta property="og:site_name" content=""><meta property="og:site_name" content="Example">`))).ToBe(nil)
+		Expect(t, og.SiteName).ToBe("Example")
+	})
+
+	When(t, "IgnoreEmptyValues is disabled", func(t *testing.T) {
+		og := New("https://example.com/")
+		og.Policy.IgnoreEmptyValues = false
+		Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+		Expect(t, og.Type).ToBe(Type(" "))
+		Expect(t, len(og.Image)).ToBe(1)
+	})
+}
+
+func TestOpenGraph_ParseNode(t *testing.T) {
+	doc, err := html.Parse(strings.NewReader(`<html><head>
+	<meta property="og:title" content="Head">
+	<meta property="og:image" content="/a.png">
+	</head><body><meta property="og:description" content="Body"></body></html>`))
+	Expect(t, err).ToBe(nil)
+	head := doc.FirstChild.FirstChild
+	Expect(t, head.Data).ToBe("head")
+
+	og := New("https://example.com/")
+	Expect(t, og.ParseNode(head)).ToBe(nil)
+	Expect(t, og.Title).ToBe("Head")
+	Expect(t, og.Image[0].URL).ToBe("/a.png")
+	Expect(t, og.Description).ToBe("")
+
+	og = New("https://example.com/")
+	Expect(t, og.ParseNode(doc)).ToBe(nil)
+	Expect(t, og.Description).ToBe("Body")
+}
+
+func TestParse_KeepNode(t *testing.T) {
+	doc := `<html><head><title>Title</title></head><body><h1>Heading</h1></body></html>`
+	og := New("https://example.com/")
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Node).ToBe((*html.Node)(nil))
+
+	og = New("https://example.com/")
+	og.Policy.KeepNode = true
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Node.Type).ToBe(html.DocumentNode)
+	body := og.Node.FirstChild.LastChild
+	Expect(t, body.FirstChild.FirstChild.Data).ToBe("Heading")
+}
+
+func TestParse_Robots(t *testing.T) {
+	og := New("https://example.com/")
+	Expect(t, og.Parse(strings.NewReader(`<title>Title</title>`))).ToBe(nil)
+	Expect(t, og.Robots.NoIndex()).ToBe(false)
+
+	og = New("https://example.com/")
+	Expect(t, og.Parse(strings.NewReader(`<meta name="robots" content="NoIndex, max-snippet:50"><meta name="googlebot" content="nofollow">`))).ToBe(nil)
+	Expect(t, og.Robots.Directives).ToBe([]string{"noindex", "max-snippet:50", "nofollow"})
+	Expect(t, og.Robots.NoIndex()).ToBe(true)
+	Expect(t, og.Robots.NoFollow()).ToBe(true)
+	Expect(t, (&Robots{Directives: []string{"none"}}).NoIndex()).ToBe(true)
+}
+
+func TestParse_Mobile(t *testing.T) {
+	doc := `<meta name="viewport" content="width=device-width, initial-scale=1">
+	<meta name="apple-mobile-web-app-capable" content="yes">`
+	og := New("https://example.com/")
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, *og.Mobile).ToBe(Mobile{Viewport: "width=device-width, initial-scale=1", WebAppCapable: true})
+
+	og = New("https://example.com/")
+	og.Policy.Strict = true
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Mobile).ToBe((*Mobile)(nil))
+}
+
+func TestParse_DateLayouts(t *testing.T) {
+	doc := `<meta property="og:type" content="article">
+	<meta property="article:published_time" content="2020-01-02T03:04:05Z">
+	<meta property="article:modified_time" content="02 Jan 2020">`
+	og := New("https://example.com/")
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Article.Published).ToBe(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC))
+	Expect(t, og.Article.ModifiedTime).ToBe("02 Jan 2020")
+	Expect(t, og.Article.Modified.IsZero()).ToBe(true)
+
+	og = New("https://example.com/")
+	og.Policy.DateLayouts = []string{"02 Jan 2006"}
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Article.Modified).ToBe(time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC))
+}
+
+func TestParse_GuessImageFromBody(t *testing.T) {
+	doc := `<html><body>
+	<img src="small.png" srcset="small.png 400w, /images/large.png 1200w, medium.png 800w">
+	<img src="second.png">
+	</body></html>`
+	og := New("https://example.com/posts/")
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, len(og.Image)).ToBe(0)
+
+	og = New("https://example.com/posts/")
+	og.Policy.GuessImageFromBody = true
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, len(og.Image)).ToBe(1)
+	Expect(t, og.Image[0].URL).ToBe("https://example.com/images/large.png")
+	Expect(t, og.Provenance["Image"]).ToBe(ProvenanceBody)
+
+	Expect(t, (&Img{Src: "a.png", Srcset: "a.png, a@3x.png 3x, a@2x.png 2x"}).Source()).ToBe("a@3x.png")
+	Expect(t, (&Img{Src: "a.png"}).Source()).ToBe("a.png")
+}
+
+func TestParse_GuessImageFromBody_Dimensions(t *testing.T) {
+	for doc, expected := range map[string][2]int{
+		`<img src="/1.png" width="1200" height="630" style="width: 600px; height: 315px">`: {1200, 630},
+		`<img src="/1.png" style="width: 1200px; HEIGHT:630.4PX">`:                         {1200, 630},
+		`<img src="/1.png" style="width: 100%; height: 20em">`:                             {0, 0},
+		`<img src="/1.png" style="width: ; height">`:                                       {0, 0},
+		`<img src="/1.png" srcset="/2.png 2x" style="width: 1200px; height: 630px">`:       {0, 0},
+	} {
+		og := New("https://example.com/")
+		og.Policy.GuessImageFromBody = true
+		Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+		Expect(t, len(og.Image)).ToBe(1)
+		Expect(t, [2]int{og.Image[0].Width, og.Image[0].Height}).ToBe(expected)
+	}
+}
+
+func TestParse_GuessDescriptionFromBody(t *testing.T) {
+	doc := `<html><body>
+	<nav><p>Home / Blog / Posts / This is a breadcrumb navigation paragraph</p></nav>
+	<p>Short intro.</p>
+	<p>This is the   first <b>substantive</b> paragraph,
+	which is long enough to describe the page.</p>
+	</body></html>`
+	og := New("https://example.com/")
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Description).ToBe("")
+
+	og = New("https://example.com/")
+	og.Policy.GuessDescriptionFromBody = true
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Description).ToBe("This is the first substantive paragraph, which is long enough to describe the page.")
+	Expect(t, og.Provenance["Description"]).ToBe(ProvenanceBody)
+
+	og = New("https://example.com/")
+	og.Policy.GuessDescriptionFromBody = true
+	Expect(t, og.Parse(strings.NewReader(`<meta name="description" content="Meta">`+doc))).ToBe(nil)
+	Expect(t, og.Description).ToBe("Meta")
+}
+
+func TestParse_GuessDateFromBody(t *testing.T) {
+	doc := `<html><body><article>
+	<time>yesterday</time>
+	<time datetime="2020-01-02">Jan 2</time>
+	<time datetime="2020-01-03">Jan 3</time>
+	</article></body></html>`
+	og := New("https://example.com/")
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Article).ToBe((*OGArticle)(nil))
+
+	og = New("https://example.com/")
+	og.Policy.GuessDateFromBody = true
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Article.PublishedTime).ToBe("2020-01-02")
+	Expect(t, og.Article.Published).ToBe(time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC))
+	Expect(t, og.Provenance["Article.PublishedTime"]).ToBe(ProvenanceBody)
+
+	og = New("https://example.com/")
+	og.Policy.GuessDateFromBody = true
+	Expect(t, og.Parse(strings.NewReader(`<meta property="article:published_time" content="2019-12-31">`+doc))).ToBe(nil)
+	Expect(t, og.Article.PublishedTime).ToBe("2019-12-31")
+}
+
+func TestParse_CollectStats(t *testing.T) {
+	doc := `<html><head>
+	<meta property="og:title" content="Title">
+	<meta property="og:image" content="https://example.com/a.png">
+	</head><body></body></html>`
+	og := New("https://example.com/")
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Stats).ToBe((*ParseStats)(nil))
+
+	og = New("https://example.com/")
+	og.Policy.CollectStats = true
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Stats.Metas).ToBe(2)
+	Expect(t, og.Stats.Images).ToBe(1)
+	Expect(t, og.Stats.Nodes > 5).ToBe(true)
+	Expect(t, og.Stats.Duration > 0).ToBe(true)
+}
+
+func BenchmarkParse(b *testing.B) {
+	doc, err := ioutil.ReadFile("test/html/01.html")
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		og := New("https://example.com/")
+		if err := og.Parse(bytes.NewReader(doc)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestParse_SiteNameFallback(t *testing.T) {
+	og := New("https://www.example.com:8080/")
+	Expect(t, og.Parse(strings.NewReader(`<meta name="application-name" content="Example App">`))).ToBe(nil)
+	Expect(t, og.SiteName).ToBe("Example App")
+	Expect(t, og.Provenance["SiteName"]).ToBe(ProvenanceApplicationName)
+
+	og = New("https://www.example.com:8080/")
+	Expect(t, og.Parse(strings.NewReader(`<title>Title</title>`))).ToBe(nil)
+	Expect(t, og.SiteName).ToBe("www.example.com:8080")
+	Expect(t, og.Provenance["SiteName"]).ToBe(ProvenanceHost)
+
+	fulfilled := New("https://www.example.com:8080/")
+	Expect(t, fulfilled.Fulfill()).ToBe(nil)
+	Expect(t, fulfilled.SiteName).ToBe(og.SiteName)
+
+	og = New("https://www.example.com/")
+	og.Policy.Strict = true
+	Expect(t, og.Parse(strings.NewReader(`<meta name="application-name" content="Example App">`))).ToBe(nil)
+	Expect(t, og.SiteName).ToBe("")
+}
+
+func TestParse_OnHeadComplete(t *testing.T) {
+	doc := `<html><head>
+	<meta property="og:title" content="Head">
+	</head><body>
+	<meta property="og:description" content="Body">
+	<img src="/body.png">
+	</body></html>`
+	calls := 0
+	og := New("https://example.com/")
+	og.Policy.GuessImageFromBody = true
+	og.Policy.OnHeadComplete = func() bool {
+		calls++
+		return og.Title == ""
+	}
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, calls).ToBe(1)
+	Expect(t, og.Title).ToBe("Head")
+	Expect(t, og.Description).ToBe("")
+	Expect(t, len(og.Image)).ToBe(0)
+
+	og = New("https://example.com/")
+	og.Policy.GuessImageFromBody = true
+	og.Policy.OnHeadComplete = func() bool { return true }
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Description).ToBe("Body")
+	Expect(t, len(og.Image)).ToBe(1)
+}
+
+func TestParse_OnMeta(t *testing.T) {
+	doc := `<html><head>
+	<meta property="og:title" content="Title">
+	<meta name="description" content="Description">
+	<meta property="og:type" content="article">
+	</head></html>`
+	seen := []string{}
+	og := New("https://example.com/")
+	og.Policy.OnMeta = func(m Meta) bool {
+		seen = append(seen, m.Content)
+		return m.Name != "description"
+	}
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, seen).ToBe([]string{"Title", "Description"})
+	Expect(t, og.Description).ToBe("Description")
+	Expect(t, og.Type).ToBe(Type(""))
+}
+
+func TestParse_ImagePropertiesOrder(t *testing.T) {
+	When(t, "dimensions follow bare og:image", func(t *testing.T) {
+		doc := `<meta property="og:image" content="https://example.com/a.png">
+		<meta property="og:image:width" content="400">
+		<meta property="og:image:height" content="300">`
+		og := New("https://example.com/")
+		Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+		Expect(t, len(og.Image)).ToBe(1)
+		Expect(t, *og.Image[0]).Deeply().ToBe(OGImage{URL: "https://example.com/a.png", Width: 400, Height: 300})
+	})
+	When(t, "dimensions precede og:image:url", func(t *testing.T) {
+		doc := `<meta property="og:image:width" content="400">
+		<meta property="og:image:height" content="300">
+		<meta property="og:image:url" content="https://example.com/a.png">
+		<meta property="og:image" content="https://example.com/b.png">`
+		og := New("https://example.com/")
+		Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+		Expect(t, len(og.Image)).ToBe(2)
+		Expect(t, *og.Image[0]).Deeply().ToBe(OGImage{URL: "https://example.com/a.png", Width: 400, Height: 300})
+		Expect(t, *og.Image[1]).Deeply().ToBe(OGImage{URL: "https://example.com/b.png"})
+	})
+}
+
+func TestOpenGraph_ContentHash(t *testing.T) {
+	doc := `<meta property="og:title" content="Title">
+	<meta property="og:image" content="/1.png">
+	<meta property="og:image:width" content="100">`
+	a, b := New("https://example.com/"), New("https://example.com/")
+	Expect(t, a.Parse(strings.NewReader(doc))).ToBe(nil)
+	b.Policy.CollectWarnings = true
+	b.Policy.CollectStats = true
+	b.Policy.KeepRaw = true
+	b.Image = make([]*OGImage, 0, 8)
+	Expect(t, b.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, len(a.ContentHash())).ToBe(64)
+	Expect(t, b.ContentHash()).ToBe(a.ContentHash())
+
+	Expect(t, (&OpenGraph{LocaleAlt: []string{}}).ContentHash()).ToBe((&OpenGraph{}).ContentHash())
+	Expect(t, (&OpenGraph{Article: &OGArticle{Tag: []string{}}}).ContentHash()).ToBe((&OpenGraph{Article: &OGArticle{}}).ContentHash())
+	Expect(t, (&OpenGraph{Image: []*OGImage{{URL: "/1.png", Renditions: []Dimension{}}}}).ContentHash()).
+		ToBe((&OpenGraph{Image: []*OGImage{{URL: "/1.png"}}}).ContentHash())
+	Expect(t, (&OpenGraph{Raw: map[string][]string{"a": {"1"}}}).ContentHash()).ToBe((&OpenGraph{}).ContentHash())
+
+	b.Image[0].Width = 200
+	Expect(t, b.ContentHash()).Not().ToBe(a.ContentHash())
+}
+
+func TestOpenGraph_AlternatesByLang(t *testing.T) {
+	doc := `<link rel="alternate" hreflang="en_US" href="/en/">
+	<link rel="alternate" hreflang="en-us" href="/en-dup/">
+	<link rel="alternate" hreflang="ja" href="https://example.jp/">
+	<link rel="alternate" hreflang="X-Default" href="/">
+	<link rel="alternate" hreflang="Tokyo" href="/tokyo/">`
+	og := New("https://example.com/posts/")
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.AlternatesByLang()).ToBe(map[string]string{
+		"en-US":     "https://example.com/en/",
+		"ja":        "https://example.jp/",
+		"x-default": "https://example.com/",
+	})
+}
+
+func TestParse_SplitLocaleAlternates(t *testing.T) {
+	doc := `<meta property="og:locale:alternate" content="en_US, fr_FR,de-DE">
+	<meta property="og:locale:alternate" content="ja_JP,Tokyo Edition">`
+	og := New("https://example.com/")
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.LocaleAlt).ToBe([]string{"en_US, fr_FR,de-DE", "ja_JP,Tokyo Edition"})
+
+	og = New("https://example.com/")
+	og.Policy.SplitLocaleAlternates = true
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.LocaleAlt).ToBe([]string{"en_US", "fr_FR", "de-DE", "ja_JP,Tokyo Edition"})
+}
+
+func TestParse_ForeignContent(t *testing.T) {
+	doc := `<html><head></head><body>
+	<svg><title>Chart</title><link rel="icon" href="/svg.png"/><a href="/svg"><text>Link</text></a></svg>
+	<math><mi><title>MathML</title></mi></math>
+	</body></html>`
+	og := New("https://example.com/")
+	og.Policy.CollectLinks = true
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Title).ToBe("")
+	Expect(t, og.Favicon).ToBe("/favicon.ico")
+	Expect(t, len(og.Links)).ToBe(0)
+}
+
+func TestParse_DuplicateWarnings(t *testing.T) {
+	doc := `<meta property="og:title" content="First">
+	<meta property="og:title" content="Second">
+	<meta property="og:type" content="website">
+	<meta property="og:type" content="website">
+	<meta property="og:image" content="https://example.com/a.png">
+	<meta property="og:image:width" content="400">
+	<meta property="og:image:width" content="400">
+	<meta property="og:image:height" content="300">
+	<meta property="og:image:type" content="image/png">
+	<meta property="og:image:secure_url" content="https://example.com/a.png">
+	<meta property="og:image:secure_url" content=" https://example.com/a.png ">
+	<meta property="og:image" content="https://example.com/b.png">
+	<meta property="og:image:width" content="200">
+	<meta property="og:image:width" content="250">
+	<meta property="og:image:height" content="100">
+	<meta property="og:image:type" content="image/png">
+	<meta property="og:audio" content="https://example.com/a.mp3">
+	<meta property="og:audio:type" content="audio/mpeg">
+	<meta property="og:audio:type" content="audio/ogg">`
+	og := New("https://example.com/")
+	og.Policy.CollectWarnings = true
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Title).ToBe("Second")
+	Expect(t, og.Image[0].Width).ToBe(400)
+	Expect(t, og.Warnings).ToBe([]Warning{
+		{Property: "og:title", Message: `declared twice: "First" and "Second"`, First: "First", Second: "Second", FirstIn: "head", SecondIn: "head"},
+		{Property: "og:image:width", Message: `declared twice: "200" and "250"`, First: "200", Second: "250"},
+		{Property: "og:audio:type", Message: `declared twice: "audio/mpeg" and "audio/ogg"`, First: "audio/mpeg", Second: "audio/ogg"},
+	})
+}
+
+func TestParse_NonPositiveDimensions(t *testing.T) {
+	doc := `<meta property="og:image" content="https://example.com/a.png">
+	<meta property="og:image:width" content="0">
+	<meta property="og:image:height" content="-1">
+	<meta property="og:video" content="https://example.com/a.mp4">
+	<meta property="og:video:width" content="-1">`
+	og := New("https://example.com/")
+	og.Policy.CollectWarnings = true
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Image[0].Width).ToBe(0)
+	Expect(t, og.Image[0].Height).ToBe(0)
+	Expect(t, og.Video[0].Width).ToBe(0)
+	Expect(t, og.Warnings[0]).ToBe(Warning{Property: "og:image:width", Message: "non-positive dimension 0 is ignored"})
+	Expect(t, og.Warnings[1]).ToBe(Warning{Property: "og:image:height", Message: "non-positive dimension -1 is ignored"})
+	Expect(t, og.Warnings[2]).ToBe(Warning{Property: "og:video:width", Message: "non-positive dimension -1 is ignored"})
+}
+
+func TestParse_ImageURLAlias(t *testing.T) {
+	When(t, "og:image:url repeats bare og:image", func(t *testing.T) {
+		doc := `<meta property="og:image" content="https://example.com/a.png">
+		<meta property="og:image:url" content="https://example.com/a.png">
+		<meta property="og:image:width" content="400">
+		<meta property="og:image:url" content="https://example.com/b.png">
+		<meta property="og:image" content="https://example.com/b.png">`
+		og := New("https://example.com/")
+		Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+		Expect(t, len(og.Image)).ToBe(2)
+		Expect(t, *og.Image[0]).Deeply().ToBe(OGImage{URL: "https://example.com/a.png", Width: 400})
+		Expect(t, og.Image[1].URL).ToBe("https://example.com/b.png")
+	})
+	When(t, "og:image:url starts each image", func(t *testing.T) {
+		doc := `<meta property="og:image:url" content="https://example.com/a.png">
+		<meta property="og:image:url" content="https://example.com/a.png">
+		<meta property="og:image:url" content="https://example.com/b.png">`
+		og := New("https://example.com/")
+		Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+		Expect(t, len(og.Image)).ToBe(3)
+	})
+	When(t, "different URLs", func(t *testing.T) {
+		doc := `<meta property="og:image" content="https://example.com/a.png">
+		<meta property="og:image:url" content="https://example.com/b.png">`
+		og := New("https://example.com/")
+		Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+		Expect(t, len(og.Image)).ToBe(2)
+	})
+}
+
+func TestParse_ImageRenditions(t *testing.T) {
+	doc := `<meta property="og:image" content="https://example.com/responsive.png">
+	<meta property="og:image:type" content="image/png">
+	<meta property="og:image:width" content="1200">
+	<meta property="og:image:height" content="630">
+	<meta property="og:image:width" content="600">
+	<meta property="og:image:height" content="315">
+	<meta property="og:image" content="https://example.com/single.png">
+	<meta property="og:image:type" content="image/png">
+	<meta property="og:image:width" content="100">
+	<meta property="og:image:height" content="100">`
+	og := New("https://example.com/")
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Image[0].Width).ToBe(600)
+	Expect(t, len(og.Image[0].Renditions)).ToBe(0)
+
+	og = New("https://example.com/")
+	og.Policy.ImageRenditions = true
+	og.Policy.CollectWarnings = true
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, len(og.Image)).ToBe(2)
+	Expect(t, og.Image[0].Width).ToBe(1200)
+	Expect(t, og.Image[0].Height).ToBe(630)
+	Expect(t, og.Image[0].Renditions).ToBe([]Dimension{{1200, 630}, {600, 315}})
+	Expect(t, len(og.Image[1].Renditions)).ToBe(0)
+	Expect(t, len(og.Warnings)).ToBe(0)
+}
+
+func TestParse_InterleavedVideos(t *testing.T) {
+	doc := `<meta property="og:video" content="https://example.com/a.mp4">
+	<meta property="og:video:url" content="https://example.com/a.mp4">
+	<meta property="og:video:secure_url" content="https://secure.example.com/a.mp4">
+	<meta property="og:video:type" content="video/mp4">
+	<meta property="og:video:width" content="1280">
+	<meta property="og:video:height" content="720">
+	<meta property="og:video" content="https://example.com/b.webm">
+	<meta property="og:video:width" content="640">
+	<meta property="og:video:url" content="https://example.com/c.swf">
+	<meta property="og:video:type" content="application/x-shockwave-flash">
+	<meta property="og:video:height" content="360">`
+	og := New("https://example.com/")
+	og.Policy.CollectWarnings = true
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, len(og.Video)).ToBe(3)
+	Expect(t, *og.Video[0]).ToBe(OGVideo{
+		URL:    "https://example.com/a.mp4",
+		SURL:   "https://secure.example.com/a.mp4",
+		Type:   "video/mp4",
+		Width:  1280,
+		Height: 720,
+	})
+	Expect(t, *og.Video[1]).ToBe(OGVideo{URL: "https://example.com/b.webm", Width: 640})
+	Expect(t, *og.Video[2]).ToBe(OGVideo{URL: "https://example.com/c.swf", Type: "application/x-shockwave-flash", Height: 360})
+	Expect(t, len(og.Warnings)).ToBe(0)
+
+	When(t, "og:video:url starts each video", func(t *testing.T) {
+		doc := `<meta property="og:video:url" content="https://example.com/a.mp4">
+		<meta property="og:video:width" content="1280">
+		<meta property="og:video:url" content="https://example.com/a.mp4">
+		<meta property="og:video:width" content="640">`
+		og := New("https://example.com/")
+		Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+		Expect(t, len(og.Video)).ToBe(2)
+		Expect(t, og.Video[0].Width).ToBe(1280)
+		Expect(t, og.Video[1].Width).ToBe(640)
+	})
+}
+
+func TestParse_PinterestRichPin(t *testing.T) {
+	doc := `<html><head>
+	<meta name="pinterest-rich-pin" content="true">
+	<meta property="og:type" content="product">
+	<meta property="og:price:amount" content="12.00">
+	<meta property="og:price:currency" content="USD">
+	<meta property="product:availability" content="in stock">
+	<meta property="product:brand" content="Example">
+	</head></html>`
+	og := New("https://example.com/")
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, *og.Product).ToBe(OGProduct{PriceAmount: "12.00", PriceCurrency: "USD", Availability: "in stock", Brand: "Example"})
+	Expect(t, og.Raw).ToBe(map[string][]string(nil))
+
+	og = New("https://example.com/")
+	og.Policy.KeepRaw = true
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.Raw["pinterest-rich-pin"]).ToBe([]string{"true"})
+	Expect(t, og.Raw["og:price:amount"]).ToBe([]string{"12.00"})
+
+	When(t, "CapturePrefixes is specified", func(t *testing.T) {
+		og := New("https://example.com/")
+		og.Policy.CapturePrefixes = []string{"product:"}
+		Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+		Expect(t, og.Raw).ToBe(map[string][]string{
+			"product:availability": {"in stock"},
+			"product:brand":        {"Example"},
+		})
+		Expect(t, og.Product.PriceAmount).ToBe("12.00")
+	})
+}
+
+func TestParse_Noscript(t *testing.T) {
+	og := New("https://example.com/")
+	err := og.Parse(strings.NewReader(`<html><head>
+	<meta property="og:title" content="Fresh">
+	<noscript><meta property="og:title" content="Stale"></noscript>
+	</head><body>
+	<noscript><meta property="og:description" content="Stale"></noscript>
+	</body></html>`))
+	Expect(t, err).ToBe(nil)
+	Expect(t, og.Title).ToBe("Fresh")
+	Expect(t, og.Description).ToBe("")
+
+	When(t, "a tree parsed with scripting disabled is given to ParseNode", func(t *testing.T) {
+		doc := `<html><head><meta property="og:title" content="Fresh"></head><body>
+		<noscript><meta property="og:title" content="Stale"><meta property="og:description" content="Stale"></noscript>
+		</body></html>`
+		node, err := html.ParseWithOptions(strings.NewReader(doc), html.ParseOptionEnableScripting(false))
+		Expect(t, err).ToBe(nil)
+
+		og := New("https://example.com/")
+		Expect(t, og.ParseNode(node)).ToBe(nil)
+		Expect(t, og.Description).ToBe("Stale")
+
+		og = New("https://example.com/")
+		og.Policy.IgnoreNoscript = true
+		Expect(t, og.ParseNode(node)).ToBe(nil)
+		Expect(t, og.Title).ToBe("Fresh")
+		Expect(t, og.Description).ToBe("")
+	})
+}
+
+func TestOpenGraph_Fetch_Hosts(t *testing.T) {
+	s := dummyServer(1)
+	defer s.Close()
+	r := httptest.NewServer(http.RedirectHandler(s.URL, http.StatusFound))
+	defer r.Close()
+
+	og := New(s.URL)
+	og.Policy.BlockedHosts = []string{"127.0.0.1"}
+	err := og.Fetch(context.Background())
+	Expect(t, err).TypeOf("*opengraph.HostNotAllowedError")
+
+	When(t, "host is allowed", func(t *testing.T) {
+		og := New(s.URL)
+		og.Policy.AllowedHosts = []string{".example.com", "127.0.0.1"}
+		err := og.Fetch(context.Background())
+		Expect(t, err).ToBe(nil)
+		Expect(t, og.Title).ToBe("Hello! Open Graph!!")
+	})
+
+	When(t, "redirect destination is not allowed", func(t *testing.T) {
+		og := New(strings.Replace(r.URL, "127.0.0.1", "localhost", 1))
+		og.Policy.AllowedHosts = []string{"localhost"}
+		err := og.Fetch(context.Background())
+		herr := new(HostNotAllowedError)
+		Expect(t, errors.As(err, &herr)).ToBe(true)
+		Expect(t, herr.Host).ToBe("127.0.0.1")
+	})
+
+	Expect(t, matchHost("www.example.com", []string{".example.com"})).ToBe(true)
+	Expect(t, matchHost("example.com", []string{".example.com"})).ToBe(true)
+	Expect(t, matchHost("badexample.com", []string{".example.com"})).ToBe(false)
+}
+
+func TestOpenGraph_Fetch_Cache(t *testing.T) {
+	hits := 0
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><head><meta property="og:title" content="Cached"><meta property="og:image" content="/a.png"><meta property="og:ttl" content="60"></head></html>`)
+	}))
+	defer s.Close()
+
+	cache := NewMemoryCache(0)
+	for i := 0; i < 2; i++ {
+		og := New(s.URL)
+		og.Policy.Cache = cache
+		Expect(t, og.Fetch(context.Background())).ToBe(nil)
+		Expect(t, og.Title).ToBe("Cached")
+		Expect(t, og.Policy.Cache).ToBe(cache)
+	}
+	Expect(t, hits).ToBe(1)
+	Expect(t, cache.entries[s.URL].expires.After(time.Now().Add(59*time.Second))).ToBe(true)
+
+	When(t, "cache is expired", func(t *testing.T) {
+		cache := NewMemoryCache(0)
+		cache.Set(s.URL, &OpenGraph{Title: "Stale"}, time.Nanosecond)
+		time.Sleep(time.Millisecond)
+		og := New(s.URL)
+		og.Policy.Cache = cache
+		Expect(t, og.Fetch(context.Background())).ToBe(nil)
+		Expect(t, og.Title).ToBe("Cached")
+		Expect(t, hits).ToBe(2)
+	})
+
+	When(t, "a hit is modified", func(t *testing.T) {
+		cache := NewMemoryCache(0)
+		rewrite := func(u string) string { return "https://proxy.example.com/?u=" + u }
+		for i := 0; i < 2; i++ {
+			og := New(s.URL)
+			og.Policy.Cache = cache
+			og.Policy.URLRewriter = rewrite
+			Expect(t, og.Fetch(context.Background())).ToBe(nil)
+			og.ToAbsURL()
+			Expect(t, og.Image[0].URL).ToBe("https://proxy.example.com/?u=" + s.URL + "/a.png")
+			og.Image[0].Alt = "Modified"
+		}
+		cached, _ := cache.Get(s.URL)
+		Expect(t, *cached.Image[0]).Deeply().ToBe(OGImage{URL: "/a.png"})
+	})
+}
+
+func TestFetchFaviconURL(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/none":
+			fmt.Fprint(w, `<html><head><title>None</title></head></html>`)
+		default:
+			fmt.Fprint(w, `<html><head><link rel="icon" href="/first.png"><link rel="icon" href="/second.png"></head></html>`)
+		}
+	}))
+	defer s.Close()
+
+	favicon, err := FetchFaviconURL(context.Background(), s.URL+"/")
+	Expect(t, err).ToBe(nil)
+	Expect(t, favicon).ToBe(s.URL + "/first.png")
+
+	favicon, err = FetchFaviconURL(context.Background(), s.URL+"/none")
+	Expect(t, err).ToBe(nil)
+	Expect(t, favicon).ToBe(s.URL + "/favicon.ico")
+}
+
+func TestFetchTitle(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/title":
+			fmt.Fprint(w, `<html><head><title> Title </title></head><body><meta property="og:title" content="Body"></body></html>`)
+		default:
+			fmt.Fprint(w, `<html><head><title>Title</title><meta property="og:title" content="First"><meta property="og:title" content="Second"></head></html>`)
+		}
+	}))
+	defer s.Close()
+
+	title, err := FetchTitle(context.Background(), s.URL+"/")
+	Expect(t, err).ToBe(nil)
+	Expect(t, title).ToBe("First")
+
+	title, err = FetchTitle(context.Background(), s.URL+"/title")
+	Expect(t, err).ToBe(nil)
+	Expect(t, title).ToBe("Title")
+
+	When(t, "charset is declared by <meta>", func(t *testing.T) {
+		s := dummyRawServer(5, "text/html")
+		defer s.Close()
+		title, err := FetchTitle(context.Background(), s.URL)
+		Expect(t, err).ToBe(nil)
+		Expect(t, title).ToBe("Привет, мир")
+	})
+}
+
+func TestOpenGraph_Fetch_ContentLength(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("Content-Length", "4096")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("<html><head><title>Large</title></head>"))
+	}))
+	defer s.Close()
+
+	og := New(s.URL)
+	og.Policy.MaxBodyBytes = 1024
+	Expect(t, og.Fetch(context.Background())).ToBe(ErrBodyTooLarge)
+	Expect(t, og.Title).ToBe("")
+}
+
+func TestOpenGraph_Fetch_Transport(t *testing.T) {
+	s := dummyServer(1)
+	defer s.Close()
+
+	og := New(s.URL)
+	Expect(t, og.httpClient()).ToBe(http.DefaultClient)
+
+	og.Policy.MaxIdleConns = 8
+	Expect(t, og.Fetch(context.Background())).ToBe(nil)
+	transport := og.httpClient().Transport.(*http.Transport)
+	Expect(t, transport.MaxIdleConns).ToBe(8)
+	Expect(t, transport.MaxIdleConnsPerHost).ToBe(8)
+	Expect(t, transport.ForceAttemptHTTP2).ToBe(true)
+
+	other := &OpenGraph{}
+	other.Policy.MaxIdleConns = 8
+	Expect(t, other.httpClient()).ToBe(og.httpClient())
+
+	custom := &http.Client{}
+	og.HTTPClient = custom
+	Expect(t, og.httpClient()).ToBe(custom)
+
+	When(t, "ForceAttemptHTTP2 is false", func(t *testing.T) {
+		force := false
+		og := &OpenGraph{}
+		og.Policy.MaxIdleConns = 8
+		og.Policy.ForceAttemptHTTP2 = &force
+		Expect(t, og.httpClient()).Not().ToBe(other.httpClient())
+		Expect(t, og.httpClient().Transport.(*http.Transport).ForceAttemptHTTP2).ToBe(false)
+	})
+}
+
+func TestOpenGraph_Fetch_PreferIPv4(t *testing.T) {
+	s := dummyServer(1)
+	defer s.Close()
+
+	og := New(s.URL)
+	og.Policy.PreferIPv4 = true
+	Expect(t, og.Fetch(context.Background())).ToBe(nil)
+	Expect(t, og.httpClient()).Not().ToBe(http.DefaultClient)
+
+	// httptest.Server listens on 127.0.0.1, which can't be dialed over IPv6.
+	og = New(s.URL)
+	og.Policy.PreferIPv6 = true
+	Expect(t, og.Fetch(context.Background())).Not().ToBe(nil)
+
+	og = New(s.URL)
+	og.HTTPClient = &http.Client{}
+	og.Policy.PreferIPv6 = true
+	Expect(t, og.Fetch(context.Background())).ToBe(nil)
+}
+
+func TestOpenGraph_Fetch_Accept(t *testing.T) {
+	var accept, custom string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accept, custom = r.Header.Get("Accept"), r.Header.Get("X-Custom")
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<title>Accept</title>`)
+	}))
+	defer s.Close()
+
+	og := New(s.URL)
+	Expect(t, og.Fetch(context.Background())).ToBe(nil)
+	Expect(t, accept).ToBe("text/html,application/xhtml+xml")
+
+	og = New(s.URL)
+	og.Policy.Header = http.Header{"Accept": {"text/html"}, "X-Custom": {"yes"}}
+	Expect(t, og.Fetch(context.Background())).ToBe(nil)
+	Expect(t, accept).ToBe("text/html")
+	Expect(t, custom).ToBe("yes")
+}
+
+func TestOpenGraph_Fetch_RedirectChain(t *testing.T) {
+	s := dummyServer(1)
+	defer s.Close()
+	r := httptest.NewServer(http.RedirectHandler(s.URL, http.StatusFound))
+	defer r.Close()
+
+	og := New(r.URL)
+	err := og.Fetch(context.Background())
+	Expect(t, err).ToBe(nil)
+	Expect(t, og.RedirectChain).ToBe([]string{r.URL, s.URL})
+
+	When(t, "redirect is rejected", func(t *testing.T) {
+		og := New(r.URL)
+		og.Policy.BlockedHosts = []string{"127.0.0.1"}
+		og.URL.URL.Host = strings.Replace(og.URL.URL.Host, "127.0.0.1", "localhost", 1)
+		err := og.Fetch(context.Background())
+		Expect(t, err).Not().ToBe(nil)
+		Expect(t, og.RedirectChain).ToBe([]string{og.URL.URL.String(), s.URL})
+	})
+}
+
+func TestOpenGraph_Fetch_MaxBodyBytes(t *testing.T) {
+	s := dummyServer(2)
+	defer s.Close()
+	og := New(s.URL)
+	og.Policy.MaxBodyBytes = 512
+	err := og.Fetch(context.Background())
+	Expect(t, err).ToBe(ErrBodyTooLarge)
+
+	og = New(s.URL)
+	og.Policy.MaxBodyBytes = 1 << 20
+	err = og.Fetch(context.Background())
+	Expect(t, err).ToBe(nil)
+}
+
+func TestOpenGraph_FetchFavicon(t *testing.T) {
+	s := dummyImageServer()
+	defer s.Close()
+	og := New(s.URL + "/posts/1")
+	og.Favicon = "/images/ok.png"
+	b, contentType, err := og.FetchFavicon(context.Background())
+	Expect(t, err).ToBe(nil)
+	Expect(t, string(b)).ToBe("ok")
+	Expect(t, contentType).ToBe("image/png")
+
+	When(t, "favicon is not found", func(t *testing.T) {
+		og.Favicon = ""
+		_, _, err := og.FetchFavicon(context.Background())
+		Expect(t, err).Not().ToBe(nil)
+	})
+
+	When(t, "favicon is too large", func(t *testing.T) {
+		og.Favicon = "/images/ok.png"
+		og.Policy.MaxBodyBytes = 1
+		_, _, err := og.FetchFavicon(context.Background())
+		Expect(t, err).ToBe(ErrBodyTooLarge)
+	})
+}
+
+func TestOpenGraph_FetchManifest(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/app/manifest.json":
+			w.Header().Set("Content-Type", "application/manifest+json")
+			w.Write([]byte(`{"name":"Example App","short_name":"Example","theme_color":"#336699","start_url":"../","icons":[{"src":"icon-192.png","sizes":"192x192","type":"image/png"}]}`))
+		default:
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<html><head><link rel="manifest" href="/app/manifest.json"></head></html>`))
+		}
+	}))
+	defer s.Close()
+
+	og, err := Fetch(s.URL + "/posts/1")
+	Expect(t, err).ToBe(nil)
+	Expect(t, og.ManifestURL).ToBe(s.URL + "/app/manifest.json")
+	manifest, err := og.FetchManifest(context.Background())
+	Expect(t, err).ToBe(nil)
+	Expect(t, manifest.Name).ToBe("Example App")
+	Expect(t, manifest.ShortName).ToBe("Example")
+	Expect(t, manifest.ThemeColor).ToBe("#336699")
+	Expect(t, manifest.StartURL).ToBe(s.URL + "/")
+	Expect(t, manifest.Icons).Deeply().ToBe([]ManifestIcon{{Src: s.URL + "/app/icon-192.png", Sizes: "192x192", Type: "image/png"}})
+
+	When(t, "no manifest is declared", func(t *testing.T) {
+		og := New(s.URL)
+		_, err := og.FetchManifest(context.Background())
+		Expect(t, err).ToBe(ErrNoManifest)
+	})
+}
+
+func TestOpenGraph_InlineFavicon(t *testing.T) {
+	s := dummyImageServer()
+	defer s.Close()
+	og := New(s.URL + "/posts/1")
+	og.Favicon = "/images/ok.png"
+	Expect(t, og.InlineFavicon(context.Background())).ToBe(nil)
+	Expect(t, og.Favicon).ToBe("data:image/png;base64,b2s=")
+	Expect(t, og.ToAbsURL().Favicon).ToBe("data:image/png;base64,b2s=")
+
+	When(t, "favicon is not an image", func(t *testing.T) {
+		og.Favicon = "/images/html.png"
+		Expect(t, og.InlineFavicon(context.Background())).Not().ToBe(nil)
+		Expect(t, og.Favicon).ToBe("/images/html.png")
+	})
+
+	When(t, "favicon is too large", func(t *testing.T) {
+		og.Favicon = "/images/ok.png"
+		og.Policy.MaxInlineFaviconBytes = 1
+		Expect(t, og.InlineFavicon(context.Background())).ToBe(ErrFaviconTooLarge)
+		Expect(t, og.Favicon).ToBe("/images/ok.png")
+	})
+
+	When(t, "favicon is served without image type", func(t *testing.T) {
+		og := New(s.URL + "/posts/1")
+		og.Favicon = "/images/favicon.ico"
+		Expect(t, og.InlineFavicon(context.Background())).ToBe(nil)
+		Expect(t, og.Favicon).ToBe("data:image/x-icon;base64,AAABAAEAAQE=")
+	})
+
+	When(t, "favicon is already inlined", func(t *testing.T) {
+		og := New("https://example.com/")
+		og.Favicon = "data:image/png;base64,b2s="
+		Expect(t, og.InlineFavicon(context.Background())).ToBe(nil)
+		Expect(t, og.Favicon).ToBe("data:image/png;base64,b2s=")
+	})
+}
+
+func TestOpenGraph_Fetch_RequestFactory(t *testing.T) {
+	var h = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			http.Redirect(w, r, "/secret", http.StatusFound)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><head><meta property="og:title" content="Secret"></head></html>`)
+	})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	og := New(s.URL + "/")
+	og.Policy.RequestFactory = func(ctx context.Context, url string) (*http.Request, error) {
+		req, err := http.NewRequest("GET", url, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer token")
+		return req, nil
+	}
+	Expect(t, og.Fetch(context.Background())).ToBe(nil)
+	Expect(t, og.Title).ToBe("Secret")
+
+	_, err := json.Marshal(og)
+	Expect(t, err).ToBe(nil)
+}
+
+func TestOpenGraph_Fetch_ContentEncoding(t *testing.T) {
+	var h = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("Content-Encoding", r.URL.Query().Get("enc"))
+		doc := `<html><head><meta property="og:title" content="Compressed"></head></html>`
+		switch r.URL.Query().Get("enc") {
+		case "br":
+			bw := brotli.NewWriter(w)
+			bw.Write([]byte(doc))
+			bw.Close()
+		default:
+			w.Write([]byte(doc))
+		}
+	})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	og, err := Fetch(s.URL + "?enc=br")
+	Expect(t, err).ToBe(nil)
+	Expect(t, og.Title).ToBe("Compressed")
+
+	_, err = Fetch(s.URL + "?enc=zstd")
+	Expect(t, err).Match("unsupported Content-Encoding: zstd")
+}
+
+func TestOpenGraph_Fetch_FollowMetaRefresh(t *testing.T) {
+	var h = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/stub":
+			fmt.Fprint(w, `<html><head><title>Redirecting</title><meta http-equiv="refresh" content="0; URL='/real'"></head></html>`)
+		case "/loop":
+			fmt.Fprint(w, `<html><head><meta http-equiv="refresh" content="0;url=/loop"></head></html>`)
+		default:
+			fmt.Fprint(w, `<html><head><meta property="og:title" content="Real"><meta http-equiv="refresh" content="5;url=/stub"></head></html>`)
+		}
+	})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	og := New(s.URL + "/stub")
+	Expect(t, og.Fetch(context.Background())).ToBe(nil)
+	Expect(t, og.Title).ToBe("Redirecting")
+
+	og = New(s.URL + "/stub")
+	og.Policy.FollowMetaRefresh = true
+	Expect(t, og.Fetch(context.Background())).ToBe(nil)
+	Expect(t, og.Title).ToBe("Real")
+	Expect(t, og.URL.Source).ToBe(s.URL + "/real")
+	Expect(t, og.RedirectChain).ToBe([]string{s.URL + "/stub", s.URL + "/real"})
+
+	og = New(s.URL + "/loop")
+	og.Policy.FollowMetaRefresh = true
+	Expect(t, og.Fetch(context.Background())).Match("stopped after 10 meta refreshes")
+
+	Expect(t, refreshURL("0;url=https://example.com/")).ToBe("https://example.com/")
+	Expect(t, refreshURL("5")).ToBe("")
+}
+
+func TestFetch_CustomScheme(t *testing.T) {
+	transport := &http.Transport{}
+	transport.RegisterProtocol("file", http.NewFileTransport(http.Dir("./test/html")))
+	og, err := Fetch("file:///01.html", &http.Client{Transport: transport})
+	Expect(t, err).ToBe(nil)
+	Expect(t, og.Title).ToBe("Hello! Open Graph!!")
+	og.ToAbsURL()
+	Expect(t, og.Image[0].URL).ToBe("file:///images/01.png")
+}
+
+func TestOpenGraph_ValidationReport(t *testing.T) {
+	og := &OpenGraph{Title: "Title", Image: []*OGImage{{URL: "https://example.com/a.png"}, {URL: "https://example.com/b.png", Width: 2, Height: 1}}}
+	og.URL.Value = "https://example.com/"
+	Expect(t, og.ValidationReport()).ToBe([]ValidationIssue{
+		{Severity: SeverityInfo, Field: "og:type", Message: "og:type is not specified and defaults to website"},
+		{Severity: SeverityWarning, Field: "og:image[0]", Message: "https://example.com/a.png lacks og:image:width or og:image:height"},
+	})
+	Expect(t, og.Validate()).Not().ToBe(nil)
+
+	issues := (&OpenGraph{Type: "website"}).ValidationReport()
+	Expect(t, len(issues)).ToBe(3)
+	Expect(t, issues[0].String()).ToBe("[error] og:title: og:title is required")
+}
+
+func TestFetchAndValidate(t *testing.T) {
+	s := dummyServer(1)
+	defer s.Close()
+	og, errs, err := FetchAndValidate(context.Background(), s.URL)
+	Expect(t, err).ToBe(nil)
+	Expect(t, og.Image[0].URL).ToBe(s.URL + "/images/01.png")
+	Expect(t, len(errs)).ToBe(1)
+	Expect(t, errs[0].Error()).ToBe("og:url is required")
+	Expect(t, og.Validate()).Match("og:url is required")
+
+	og.URL.Value = s.URL
+	Expect(t, og.Validate()).ToBe(nil)
+	og.Type = "unknown"
+	Expect(t, og.Validate()).Match("og:type is unknown: unknown")
+	og.Type = "myapp:recipe"
+	Expect(t, og.Validate()).ToBe(nil)
+
+	_, errs, err = FetchAndValidate(context.Background(), ":invalid_url")
+	Expect(t, err).Not().ToBe(nil)
+	Expect(t, len(errs)).ToBe(0)
+}
+
+func TestFetchAll(t *testing.T) {
+	s1, s2 := dummyServer(1), dummyServer(2)
+	defer s1.Close()
+	defer s2.Close()
+	urls := []string{s1.URL, ":invalid_url", s2.URL}
+
+	results := FetchAll(context.Background(), urls, FetchAllOptions{Concurrency: 2})
+	Expect(t, len(results)).ToBe(3)
+	Expect(t, results[0].Title).ToBe("Hello! Open Graph!!")
+	Expect(t, results[1].Error).Not().ToBe(nil)
+	Expect(t, results[2].Description).ToBe("All Genre Music Party")
+
+	When(t, "RequestsPerSecond is specified", func(t *testing.T) {
+		begin := time.Now()
+		results := FetchAll(context.Background(), []string{s1.URL, s1.URL, s1.URL}, FetchAllOptions{RequestsPerSecond: 20})
+		Expect(t, time.Since(begin) >= 150*time.Millisecond).ToBe(true)
+		Expect(t, results[2].Error).ToBe(nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		results = FetchAll(ctx, []string{s1.URL}, FetchAllOptions{RequestsPerSecond: 1})
+		Expect(t, results[0].Error).ToBe(context.Canceled)
+	})
+
+	When(t, "RequestsPerSecond is too large for an interval", func(t *testing.T) {
+		results := FetchAll(context.Background(), []string{s1.URL}, FetchAllOptions{RequestsPerSecond: 2e9})
+		Expect(t, results[0].Error).ToBe(nil)
+	})
+}
+
+func TestOpenGraph_ParseWithContext(t *testing.T) {
+	og := New("https://example.com/")
+	err := og.ParseWithContext(context.Background(), strings.NewReader(`<meta property="og:title" content="Hello">`))
+	Expect(t, err).ToBe(nil)
+	Expect(t, og.Title).ToBe("Hello")
+
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	go func() {
+		pw.Write([]byte(`<html><head><meta property="og:title" content="Slow">`))
+		time.Sleep(200 * time.Millisecond)
+		pw.Write([]byte(`</head></html>`))
+		pw.Close()
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	og = New("https://example.com/")
+	err = og.ParseWithContext(ctx, pr)
+	Expect(t, err).ToBe(context.DeadlineExceeded)
+	Expect(t, og.Title).ToBe("Slow")
+}
+
+func TestOpenGraph_Parse_Partial(t *testing.T) {
+	broken := errors.New("connection reset")
+	body := strings.NewReader(`<html><head><meta property="og:title" content="Hello"><meta property="og:type" content="article">`)
+	og := New("https://example.com/")
+	err := og.Parse(&brokenReader{r: body, err: broken})
+	Expect(t, err).ToBe(broken)
+	Expect(t, og.Title).ToBe("Hello")
+	Expect(t, og.Type).ToBe(Type("article"))
+}
+
+// brokenReader fails with err once r is exhausted.
+type brokenReader struct {
+	r   io.Reader
+	err error
+}
+
+func (br *brokenReader) Read(p []byte) (int, error) {
+	n, err := br.r.Read(p)
+	if err == io.EOF {
+		return n, br.err
+	}
+	return n, err
+}
+
+func TestFetchWithContext(t *testing.T) {
+	s := dummySlowServer(time.Millisecond * 300)
+	defer s.Close()
+
+	ctx500ms, cancel500ms := context.WithTimeout(context.Background(), time.Millisecond*500)
+	_, err := FetchWithContext(ctx500ms, s.URL)
+	Expect(t, err).ToBe(nil)
+	cancel500ms()
+
+	ctx100ms, cancel100ms := context.WithTimeout(context.Background(), time.Millisecond*100)
+	_, err = FetchWithContext(ctx100ms, s.URL)
+	Expect(t, err).Match(context.DeadlineExceeded.Error())
+	cancel100ms()
+}
+
+func TestFetch_06_PreferMetaCharset(t *testing.T) {
+	s := dummyRawServer(5, "text/html; charset=utf-8")
+	defer s.Close()
+
+	og, err := Fetch(s.URL)
+	Expect(t, err).ToBe(nil)
+	Expect(t, og.Title).Not().ToBe("Привет, мир")
+
+	When(t, "PreferMetaCharset is set", func(t *testing.T) {
+		og := New(s.URL)
+		og.Policy.PreferMetaCharset = true
+		err := og.Fetch(context.Background())
+		Expect(t, err).ToBe(nil)
+		Expect(t, og.Title).ToBe("Привет, мир")
+	})
+
+	When(t, "UTF-8 document declares no charset after a long ASCII head", func(t *testing.T) {
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, longASCIIHead)
+		}))
+		defer s.Close()
+		og, err := Fetch(s.URL)
+		Expect(t, err).ToBe(nil)
+		Expect(t, og.Title).ToBe("はいさいナイト")
+	})
+
+	When(t, "Content-Type header has no charset", func(t *testing.T) {
+		s := dummyRawServer(5, "text/html")
+		defer s.Close()
+		og, err := Fetch(s.URL)
+		Expect(t, err).ToBe(nil)
+		Expect(t, og.Title).ToBe("Привет, мир")
+	})
+}
+
+func TestFetchRaw(t *testing.T) {
+	s := dummyRawServer(5, "text/html")
+	defer s.Close()
+
+	og, raw, err := FetchRaw(context.Background(), s.URL)
+	Expect(t, err).ToBe(nil)
+	Expect(t, og.Title).ToBe("Привет, мир")
+	Expect(t, utf8.Valid(raw)).ToBe(true)
+	Expect(t, strings.Contains(string(raw), `content="Привет, мир"`)).ToBe(true)
+
+	When(t, "fetch fails", func(t *testing.T) {
+		_, raw, err := FetchRaw(context.Background(), "https://example.com/\x00")
+		Expect(t, err).Not().ToBe(nil)
+		Expect(t, len(raw)).ToBe(0)
+	})
+
+	When(t, "MaxBodyBytes is set by the method form", func(t *testing.T) {
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			// Flushing sends the body chunked without Content-Length, which is caught while reading.
+			fmt.Fprint(w, `<html><head><meta property="og:title" content="Truncated">`)
+			w.(http.Flusher).Flush()
+			fmt.Fprint(w, strings.Repeat(" ", 1024)+`</head></html>`)
+		}))
+		defer s.Close()
+		og := New(s.URL)
+		og.Policy.MaxBodyBytes = 64
+		raw, err := og.FetchRaw(context.Background())
+		Expect(t, err).ToBe(ErrBodyTooLarge)
+		Expect(t, og.Title).ToBe("Truncated")
+		Expect(t, len(raw) <= 64+1).ToBe(true)
+		Expect(t, strings.HasPrefix(string(raw), `<html><head><meta property="og:title" content="Truncated">`)).ToBe(true)
+	})
+}
+
+func TestOpenGraph_ParseReaderWithCharset(t *testing.T) {
+	b, err := ioutil.ReadFile("./test/html/05.html")
+	Expect(t, err).ToBe(nil)
+
+	og := New("https://example.com/")
+	Expect(t, og.Parse(bytes.NewReader(b))).ToBe(nil)
+	Expect(t, og.Title).Not().ToBe("Привет, мир")
+
+	og = New("https://example.com/")
+	Expect(t, og.ParseReaderWithCharset(bytes.NewReader(b), "")).ToBe(nil)
+	Expect(t, og.Title).ToBe("Привет, мир")
+
+	og = New("https://example.com/")
+	Expect(t, og.ParseReaderWithCharset(bytes.NewReader(b), "windows-1251")).ToBe(nil)
+	Expect(t, og.Title).ToBe("Привет, мир")
+
+	When(t, "UTF-8 document declares no charset after a long ASCII head", func(t *testing.T) {
+		og := New("https://example.com/")
+		Expect(t, og.ParseReaderWithCharset(strings.NewReader(longASCIIHead), "")).ToBe(nil)
+		Expect(t, og.Title).ToBe("はいさいナイト")
+	})
+}
+
+// longASCIIHead is a UTF-8 document without charset declaration, whose first 1024 bytes are ASCII.
+var longASCIIHead = `<html><head><meta name="description" content="` + strings.Repeat("a", 1100) + `">
+<meta property="og:title" content="はいさいナイト"></head></html>`
+
+func TestFromMap(t *testing.T) {
+	og := FromMap(map[string][]string{
+		"og:title":        {"Hello"},
+		"og:image":        {"/1.png", "/2.png"},
+		"og:image:width":  {"100", "200"},
+		"og:image:height": {"50"},
+		"og:video:url":    {"/1.mp4"},
+		"article:tag":     {"go", "ogp"},
+	})
+	Expect(t, og.Title).ToBe("Hello")
+	Expect(t, len(og.Image)).ToBe(2)
+	Expect(t, og.Image[0].Width).ToBe(100)
+	Expect(t, og.Image[0].Height).ToBe(50)
+	Expect(t, og.Image[1].URL).ToBe("/2.png")
+	Expect(t, og.Image[1].Width).ToBe(200)
+	Expect(t, og.Image[1].Height).ToBe(0)
+	Expect(t, og.Video[0].URL).ToBe("/1.mp4")
+	Expect(t, og.Article.Tag).ToBe([]string{"go", "ogp"})
+}
+
+func TestFromOrderedPairs(t *testing.T) {
+	// The example of arrays at ogp.me.
+	og := FromOrderedPairs([]Pair{
+		{"og:image", "https://example.com/rock.jpg"},
+		{"og:image:width", "300"},
+		{"og:image:height", "300"},
+		{"og:image", "https://example.com/rock2.jpg"},
+		{"og:image", "https://example.com/rock3.jpg"},
+		{"og:image:height", "1000"},
+	})
+	Expect(t, len(og.Image)).ToBe(3)
+	Expect(t, *og.Image[0]).Deeply().ToBe(OGImage{URL: "https://example.com/rock.jpg", Width: 300, Height: 300})
+	Expect(t, *og.Image[1]).Deeply().ToBe(OGImage{URL: "https://example.com/rock2.jpg"})
+	Expect(t, *og.Image[2]).Deeply().ToBe(OGImage{URL: "https://example.com/rock3.jpg", Height: 1000})
+
+	og = FromOrderedPairs([]Pair{
+		{"og:title", "First"},
+		{"og:title", "Second"},
+		{"og:video:width", "640"},
+	})
+	Expect(t, og.Title).ToBe("Second")
+	Expect(t, len(og.Video)).ToBe(0)
+}
+
+func TestOpenGraph_Diff(t *testing.T) {
+	a := New("https://example.com/")
+	a.Title = "Old"
+	a.Image = append(a.Image, &OGImage{URL: "/1.png", Width: 100})
+	b := New("https://example.com/")
+	b.Title = "New"
+	b.Image = append(b.Image, &OGImage{URL: "/1.png", Width: 200}, &OGImage{URL: "/2.png"})
+	b.Facebook = &Facebook{AppID: "123"}
+
+	Expect(t, a.Diff(a)).ToBe([]Difference{})
+	Expect(t, a.Diff(b)).ToBe([]Difference{
+		{Field: "Title", Old: "Old", New: "New"},
+		{Field: `Image["/1.png"].Width`, Old: "100", New: "200"},
+		{Field: `Image["/2.png"]`, Old: "", New: "/2.png"},
+		{Field: "Facebook.AppID", Old: "", New: "123"},
+	})
+
+	When(t, "an image is inserted at the front", func(t *testing.T) {
+		c := New("https://example.com/")
+		c.Title = "Old"
+		c.Image = append(c.Image, &OGImage{URL: "/0.png"}, &OGImage{URL: "/1.png", Width: 100})
+		Expect(t, a.Diff(c)).ToBe([]Difference{
+			{Field: `Image["/0.png"]`, Old: "", New: "/0.png"},
+		})
+		Expect(t, c.Diff(a)).ToBe([]Difference{
+			{Field: `Image["/0.png"]`, Old: "/0.png", New: ""},
+		})
+	})
+}
+
+func TestOpenGraph_Parse_BOM(t *testing.T) {
+	doc := `<html><head><meta property="og:title" content="Hello"></head><body></body></html>`
+	og := New("https://example.com/")
+	og.Policy.StopAtBody = true
+	Expect(t, og.Parse(strings.NewReader("\xEF\xBB\xBF"+doc))).ToBe(nil)
+	Expect(t, og.Title).ToBe("Hello")
+
+	When(t, "UTF-16 with BOM", func(t *testing.T) {
+		b, err := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder().String(doc)
+		Expect(t, err).ToBe(nil)
+		og := New("https://example.com/")
+		Expect(t, og.Parse(strings.NewReader(b))).ToBe(nil)
+		Expect(t, og.Title).ToBe("Hello")
+	})
+}
+
+func TestCharsetFromMeta(t *testing.T) {
+	meta := func(attrs ...string) *html.Node {
+		n := &html.Node{Type: html.ElementNode, Data: "meta"}
+		for i := 0; i < len(attrs); i += 2 {
+			n.Attr = append(n.Attr, html.Attribute{Key: attrs[i], Val: attrs[i+1]})
+		}
+		return n
+	}
+	Expect(t, CharsetFromMeta(meta("charset", "Shift_JIS"))).ToBe("Shift_JIS")
+	Expect(t, CharsetFromMeta(meta("http-equiv", "Content-Type", "content", "text/html; charset=euc-jp"))).ToBe("euc-jp")
+	Expect(t, CharsetFromMeta(meta("http-equiv", "refresh", "content", "0; charset=euc-jp"))).ToBe("")
+	Expect(t, CharsetFromMeta(meta("name", "description", "content", "charset"))).ToBe("")
+	Expect(t, CharsetFromMeta(&html.Node{Type: html.ElementNode, Data: "link"})).ToBe("")
+}
+
+func TestBatchParse(t *testing.T) {
+	docs := [][]byte{
+		[]byte(`<meta property="og:title" content="First"><meta property="og:type" content="">`),
+		[]byte(`<title>Second</title>`),
+		[]byte(`<meta property="og:title" content="Third">`),
+	}
+	policy := DefaultPolicy()
+	policy.Strict = true
+	results := BatchParse(docs, policy, 2)
+	Expect(t, len(results)).ToBe(3)
+	Expect(t, results[0].Title).ToBe("First")
+	Expect(t, results[1].Title).ToBe("")
+	Expect(t, results[2].Title).ToBe("Third")
+	Expect(t, results[0].Error).ToBe(nil)
+
+	results[0].Policy.TrustedTags[0] = "changed"
+	Expect(t, results[1].Policy.TrustedTags[0]).ToBe(HTMLMetaTag)
+	Expect(t, policy.TrustedTags[0]).ToBe(HTMLMetaTag)
+}
+
+func TestSummarize(t *testing.T) {
+	a := &OpenGraph{SiteName: "Example", Locale: "en_US", Image: []*OGImage{{URL: "https://example.com/a.png"}}}
+	b := &OpenGraph{SiteName: "Example Blog", Locale: "ja_JP", Image: []*OGImage{{URL: "https://example.com/b.png"}, {URL: "https://example.com/a.png"}}}
+	c := &OpenGraph{SiteName: "Example Blog"}
+	summary := Summarize([]*OpenGraph{a, nil, b, c})
+	Expect(t, summary.Pages).ToBe(3)
+	Expect(t, summary.SiteName).ToBe("Example Blog")
+	Expect(t, summary.Locale).ToBe("en_US")
+	Expect(t, summary.Type).ToBe("")
+	Expect(t, summary.Images).ToBe([]string{"https://example.com/a.png", "https://example.com/b.png"})
+}
+
+func TestParseType_Determiner_Locale(t *testing.T) {
+	typ, err := ParseType(" video.movie ")
+	Expect(t, err).ToBe(nil)
+	Expect(t, typ).ToBe(Type("video.movie"))
+	Expect(t, Type("myapp:recipe").Valid()).ToBe(true)
+	_, err = ParseType("unknown")
+	Expect(t, err).Not().ToBe(nil)
+
+	determiner, err := ParseDeterminer("The")
+	Expect(t, err).ToBe(nil)
+	Expect(t, determiner).ToBe(DeterminerThe)
+	Expect(t, DeterminerNone.Valid()).ToBe(true)
+	_, err = ParseDeterminer("some")
+	Expect(t, err).Not().ToBe(nil)
+
+	locale, err := ParseLocale("en_US")
+	Expect(t, err).ToBe(nil)
+	Expect(t, locale.String()).ToBe("en_US")
+	_, err = ParseLocale("english")
+	Expect(t, err).Not().ToBe(nil)
+
+	og := &OpenGraph{Type: "article", Determiner: DeterminerAn, Locale: "en_US"}
+	b, err := json.Marshal(og)
+	Expect(t, err).ToBe(nil)
+	Expect(t, strings.Contains(string(b), `"Type":"article"`)).ToBe(true)
+	Expect(t, strings.Contains(string(b), `"Determiner":"an","Locale":"en_US"`)).ToBe(true)
+	decoded := &OpenGraph{}
+	Expect(t, json.Unmarshal(b, decoded)).ToBe(nil)
+	Expect(t, decoded.Type).ToBe(og.Type)
+	Expect(t, decoded.Determiner).ToBe(og.Determiner)
+	Expect(t, decoded.Locale).ToBe(og.Locale)
+}
+
+func TestOpenGraph_TitleWithDeterminer(t *testing.T) {
+	og := New("https://example.com/")
+	Expect(t, og.Parse(strings.NewReader(`<meta property="og:title" content="Great Gatsby"><meta property="og:determiner" content="the">`))).ToBe(nil)
+	Expect(t, og.Determiner).ToBe(DeterminerThe)
+	Expect(t, og.TitleWithDeterminer()).ToBe("the Great Gatsby")
+	Expect(t, og.Title).ToBe("Great Gatsby")
+
+	Expect(t, (&OpenGraph{Title: "Apple", Determiner: "auto"}).TitleWithDeterminer()).ToBe("an Apple")
+	Expect(t, (&OpenGraph{Title: "Book", Determiner: "auto"}).TitleWithDeterminer()).ToBe("a Book")
+	Expect(t, (&OpenGraph{Title: "Book"}).TitleWithDeterminer()).ToBe("Book")
+}
+
+func TestOpenGraph_BestImageForSize(t *testing.T) {
+	og := &OpenGraph{Image: []*OGImage{
+		{URL: "large.png", Width: 1200, Height: 630},
+		{URL: "unknown.png"},
+		{URL: "medium.png", Width: 600, Height: 315},
+		{URL: "small.png", Width: 100, Height: 100},
+	}}
+	img, ok := og.BestImageForSize(300, 157)
+	Expect(t, ok).ToBe(true)
+	Expect(t, img.URL).ToBe("medium.png")
+
+	img, ok = og.BestImageForSize(2000, 1000)
+	Expect(t, ok).ToBe(true)
+	Expect(t, img.URL).ToBe("large.png")
+
+	_, ok = (&OpenGraph{}).BestImageForSize(1, 1)
+	Expect(t, ok).ToBe(false)
+}
+
+func TestOGImage_DimensionsFromURL(t *testing.T) {
+	w, h := (&OGImage{URL: "https://cdn.example.com/a.png?w=1200&h=630"}).DimensionsFromURL()
+	Expect(t, w).ToBe(1200)
+	Expect(t, h).ToBe(630)
+	w, h = (&OGImage{URL: "https://cdn.example.com/a.png?width=800&height=-1"}).DimensionsFromURL()
+	Expect(t, w).ToBe(800)
+	Expect(t, h).ToBe(0)
+	w, h = (&OGImage{URL: "/a.png"}).DimensionsFromURL()
+	Expect(t, w).ToBe(0)
+	Expect(t, h).ToBe(0)
+}
+
+func TestOpenGraph_IsType(t *testing.T) {
+	og := &OpenGraph{}
+	Expect(t, og.IsWebsite()).ToBe(true)
+	Expect(t, og.IsArticle()).ToBe(false)
+	og.Type = "video.movie"
+	Expect(t, og.IsVideo()).ToBe(true)
+	Expect(t, og.IsWebsite()).ToBe(false)
+	og.Type = "music.song"
+	Expect(t, og.IsMusic()).ToBe(true)
+	og.Type = "article"
+	Expect(t, og.IsArticle()).ToBe(true)
+	og.Type = "profile"
+	Expect(t, og.IsProfile()).ToBe(true)
+	og.Type = "product.item"
+	Expect(t, og.IsProduct()).ToBe(true)
+}
+
+func TestOpenGraph_ToMap(t *testing.T) {
+	doc := `<html><head>
+	<meta property="og:title" content="Title">
+	<meta property="og:image" content="https://example.com/a.png">
+	<meta property="og:image:width" content="400">
+	<meta property="og:image" content="https://example.com/b.png">
+	<meta property="og:image:alt" content="second">
+	</head></html>`
+	og := New("https://example.com/")
+	Expect(t, og.Parse(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, og.ToMap()).ToBe(map[string]string{
+		"title":       "Title",
+		"site_name":   "example.com",
+		"image":       "https://example.com/a.png",
+		"image:width": "400",
+	})
+}
+
+func TestOpenGraph_ToJSONLD(t *testing.T) {
+	og := New("https://example.com/posts/1")
+	err := og.Parse(strings.NewReader(`<html><head>
+	<meta property="og:type" content="article">
+	<meta property="og:title" content="Hello">
+	<meta property="og:image" content="https://example.com/1.png">
+	<meta property="article:published_time" content="2020-10-10T10:00:00Z">
+	</head></html>`))
+	Expect(t, err).ToBe(nil)
+	b, err := og.ToJSONLD()
+	Expect(t, err).ToBe(nil)
+	Expect(t, string(b)).ToBe(`{"@context":"https://schema.org","@type":"Article","datePublished":"2020-10-10T10:00:00Z","headline":"Hello","image":["https://example.com/1.png"],"url":"https://example.com/posts/1"}`)
+
+	When(t, "og:type is not article", func(t *testing.T) {
+		og := New("https://example.com/")
+		og.Title = "Top"
+		b, err := og.ToJSONLD()
+		Expect(t, err).ToBe(nil)
+		Expect(t, string(b)).ToBe(`{"@context":"https://schema.org","@type":"WebPage","headline":"Top","url":"https://example.com/"}`)
+	})
+
+	When(t, "og:image is relative", func(t *testing.T) {
+		og := New("https://example.com/posts/1")
+		Expect(t, og.Parse(strings.NewReader(`<meta property="og:image" content="/images/1.png">`))).ToBe(nil)
+		b, err := og.ToJSONLD()
+		Expect(t, err).ToBe(nil)
+		Expect(t, strings.Contains(string(b), `"image":["https://example.com/images/1.png"]`)).ToBe(true)
+		Expect(t, og.Image[0].URL).ToBe("/images/1.png")
+	})
+}
+
+func TestKnownTypes(t *testing.T) {
+	types := KnownTypes()
+	Expect(t, types[0]).ToBe("website")
+	types[0] = "modified"
+	Expect(t, KnownTypes()[0]).ToBe("website")
+	Expect(t, IsKnownType("video.movie")).ToBe(true)
+	Expect(t, IsKnownType("video")).ToBe(false)
+	Expect(t, IsKnownType("product")).ToBe(false)
+	Expect(t, Type("product").Valid()).ToBe(true)
+
+	props := KnownProperties()
+	Expect(t, props["og:image:width"]).ToBe(true)
+	delete(props, "og:image:width")
+	Expect(t, KnownProperties()["og:image:width"]).ToBe(true)
+}
+
+func TestTruncate(t *testing.T) {
+	og := New("https://example.com/")
+	og.Description = "はいさいナイト All Genre Music Party"
+	Expect(t, og.TruncatedDescription(8)).ToBe("はいさいナイト…")
+	Expect(t, og.TruncatedDescription(100)).ToBe(og.Description)
+	Expect(t, og.TruncatedDescription(0)).ToBe("")
+
+	Expect(t, og.Parse(strings.NewReader(`<html><head>
+	<meta property="og:image" content="/1.png">
+	<meta property="og:image:alt" content="A long alternative text">
+	</head></html>`))).ToBe(nil)
+	Expect(t, og.Image[0].TruncatedAlt(7)).ToBe("A long…")
+}
+
+func TestParse_MaxDescriptionRunes(t *testing.T) {
+	og := New("https://example.com/")
+	og.Policy.MaxDescriptionRunes = 12
+	err := og.Parse(strings.NewReader(`<html><head>
+	<meta property="og:title" content="Hello brave new world">
+	<meta property="og:description" content="はいさいナイトへようこそ、みなさん">
+	</head></html>`))
+	Expect(t, err).ToBe(nil)
+	Expect(t, og.Title).ToBe("Hello brave…")
+	Expect(t, og.Description).ToBe("はいさいナイトへようこ…")
+
+	When(t, "texts are short enough", func(t *testing.T) {
+		og := New("https://example.com/")
+		og.Policy.MaxDescriptionRunes = 12
+		err := og.Parse(strings.NewReader(`<meta property="og:title" content="Hello world">`))
+		Expect(t, err).ToBe(nil)
+		Expect(t, og.Title).ToBe("Hello world")
+	})
+
+	When(t, "the cut falls on a space", func(t *testing.T) {
+		Expect(t, truncateWords("Hello world again", 6)).ToBe("Hello…")
+		Expect(t, truncateWords("Hello world again", 12)).ToBe("Hello world…")
+		Expect(t, truncateWords("Extraordinary", 6)).ToBe("Extra…")
+	})
+}
+
+func TestLocale(t *testing.T) {
+	Expect(t, Locale("en_US").Language()).ToBe("en")
+	Expect(t, Locale("en_US").Region()).ToBe("US")
+	Expect(t, Locale("pt-br").Region()).ToBe("BR")
+	Expect(t, Locale("es_419").Region()).ToBe("419")
+	Expect(t, Locale("ja").Language()).ToBe("ja")
+	Expect(t, Locale("ja").Region()).ToBe("")
+	Expect(t, Locale("english").Language()).ToBe("")
+	Expect(t, Locale("en__US").Region()).ToBe("")
+	Expect(t, Locale("").Language()).ToBe("")
+
+	og := New("https://example.com/")
+	err := og.Parse(strings.NewReader(`<html><head>
+	<meta property="og:locale" content="en_GB">
+	<meta property="og:locale:alternate" content="fr_FR">
+	</head></html>`))
+	Expect(t, err).ToBe(nil)
+	Expect(t, og.Locale.Region()).ToBe("GB")
+	Expect(t, og.LocaleAlt).ToBe([]string{"fr_FR"})
+}
+
+func TestOpenGraph_Fetch_PreferredLocale(t *testing.T) {
+	var h = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, `<html><head>
+		<meta property="og:title" content="%s">
+		<link rel="alternate" hreflang="fr-CA" href="https://example.com/ca/">
+		<link rel="alternate" hreflang="fr-FR" href="https://example.com/fr/">
+		<link rel="alternate" hreflang="de" href="https://example.com/de/">
+		</head></html>`, r.Header.Get("Accept-Language"))
+	})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	og := New(s.URL)
+	og.Policy.PreferredLocale = "fr_FR"
+	Expect(t, og.Fetch(context.Background())).ToBe(nil)
+	Expect(t, og.PreferredAlternate).ToBe("https://example.com/fr/")
+	Expect(t, og.Title).ToBe("fr-FR,fr;q=0.9")
+
+	og = New(s.URL)
+	og.Policy.PreferredLocale = "de_AT"
+	Expect(t, og.Fetch(context.Background())).ToBe(nil)
+	Expect(t, og.PreferredAlternate).ToBe("https://example.com/de/")
+
+	og = New(s.URL)
+	og.Policy.PreferredLocale = "ja_JP"
+	Expect(t, og.Fetch(context.Background())).ToBe(nil)
+	Expect(t, og.PreferredAlternate).ToBe("")
+}
+
+func TestOGImage_AspectRatio(t *testing.T) {
+	Expect(t, (&OGImage{Width: 1200, Height: 600}).AspectRatio()).ToBe(2.0)
+	Expect(t, (&OGImage{Width: 1200}).AspectRatio()).ToBe(0.0)
+	og := New("https://example.com/")
+	Expect(t, og.PrimaryImageAspect()).ToBe(0.0)
+	og.Image = append(og.Image, &OGImage{Width: 800, Height: 400}, &OGImage{Width: 1, Height: 1})
+	Expect(t, og.PrimaryImageAspect()).ToBe(2.0)
+}
+
+func TestOGImage_GuessType(t *testing.T) {
+	Expect(t, (&OGImage{URL: "https://example.com/a.PNG?w=100"}).GuessType()).ToBe("image/png")
+	Expect(t, (&OGImage{URL: "/a.jpg"}).GuessType()).ToBe("image/jpeg")
+	Expect(t, (&OGImage{URL: "/a.jpg", Type: "image/webp"}).GuessType()).ToBe("image/webp")
+	Expect(t, (&OGImage{URL: "/image?src=a.png"}).GuessType()).ToBe("")
+	Expect(t, (&OGImage{URL: "/image.php?id=1"}).GuessType()).ToBe("")
+}
+
+func TestOpenGraph_SortImagesBySize(t *testing.T) {
+	og := New("https://example.com/")
+	og.Image = []*OGImage{
+		{URL: "/a.png"},
+		{URL: "/small.png", Width: 100, Height: 100},
+		{URL: "/b.png", Width: 100},
+		{URL: "/large.png", Width: 1200, Height: 630},
+	}
+	sorted := og.SortedImages()
+	Expect(t, og.Image[0].URL).ToBe("/a.png")
+	og.SortImagesBySize()
+	for i, want := range []string{"/large.png", "/small.png", "/a.png", "/b.png"} {
+		Expect(t, og.Image[i].URL).ToBe(want)
+		Expect(t, sorted[i].URL).ToBe(want)
+	}
+}
+
+func TestOpenGraph_FilterSecureImages(t *testing.T) {
+	og := New("https://example.com/")
+	og.Image = []*OGImage{
+		{URL: "/relative.png"},
+		{URL: "http://example.com/insecure.png"},
+		{URL: "http://example.com/with-secure.png", SURL: "https://example.com/with-secure.png"},
+	}
+	Expect(t, og.HasInsecureImages()).ToBe(true)
+	og.FilterSecureImages()
+	Expect(t, len(og.Image)).ToBe(2)
+	Expect(t, og.Image[1].URL).ToBe("http://example.com/with-secure.png")
+	Expect(t, og.HasInsecureImages()).ToBe(false)
+}
+
+func TestOpenGraph_ValidateImages(t *testing.T) {
+	s := dummyImageServer()
+	defer s.Close()
+	og := New(s.URL)
+	og.Image = []*OGImage{
+		{URL: "/images/ok.png"},
+		{URL: "/images/notfound.png"},
+		{URL: s.URL + "/images/html.png"},
+		{URL: s.URL + "/images/ok.jpg"},
+	}
+	images, err := og.ValidateImages(context.Background())
+	Expect(t, err).ToBe(nil)
+	Expect(t, len(images)).ToBe(2)
+	Expect(t, images[0].URL).ToBe("/images/ok.png")
+	Expect(t, images[1].URL).ToBe(s.URL + "/images/ok.jpg")
+	Expect(t, len(og.Image)).ToBe(4)
+
+	When(t, "context is canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		images, err := og.ValidateImages(ctx)
+		Expect(t, err).ToBe(context.Canceled)
+		Expect(t, images).ToBe(og.Image)
+	})
+}
+
+func TestOpenGraph_ResolveImageDimensions(t *testing.T) {
+	s := dummyImageServer()
+	defer s.Close()
+	og := New(s.URL)
+	og.Image = []*OGImage{
+		{URL: "/images/3x2.png"},
+		{URL: "/images/2x1.webp"},
+		{URL: "/images/1x1.avif"},
+		{URL: "/images/ok.png", Width: 10, Height: 20},
+	}
+	err := og.ResolveImageDimensions(context.Background())
+	Expect(t, err).ToBe(nil)
+	Expect(t, og.Image[0].Width).ToBe(3)
+	Expect(t, og.Image[0].Height).ToBe(2)
+	Expect(t, og.Image[1].Width).ToBe(2)
+	Expect(t, og.Image[1].Height).ToBe(1)
+	Expect(t, og.Image[2].Width).ToBe(0)
+	Expect(t, og.Image[3].Width).ToBe(10)
+}
+
+func TestOpenGraph_ResolveImageDimensions_SVG(t *testing.T) {
+	s := dummyImageServer()
+	defer s.Close()
+	og := New(s.URL)
+	og.Image = []*OGImage{{URL: "/images/1200x630.svg"}}
+	Expect(t, og.ResolveImageDimensions(context.Background())).ToBe(nil)
+	Expect(t, og.Image[0].Width).ToBe(0)
+
+	og.Policy.ResolveSVGDimensions = true
+	og.Image = []*OGImage{
+		{URL: "/images/1200x630.svg"},
+		{URL: "/images/viewbox.svg"},
+		{URL: "/images/unknown.svg"},
+		{URL: "/images/3x2.png"},
+	}
+	Expect(t, og.ResolveImageDimensions(context.Background())).ToBe(nil)
+	Expect(t, og.Image[0].Width).ToBe(1200)
+	Expect(t, og.Image[0].Height).ToBe(630)
+	Expect(t, og.Image[1].Width).ToBe(600)
+	Expect(t, og.Image[1].Height).ToBe(315)
+	Expect(t, og.Image[2].Width).ToBe(0)
+	Expect(t, og.Image[2].Height).ToBe(0)
+	Expect(t, og.Image[3].Width).ToBe(3)
+}
+
+type stubImageFetcher map[string][]byte
+
+func (f stubImageFetcher) Fetch(ctx context.Context, url string) (io.ReadCloser, http.Header, error) {
+	b, ok := f[url]
+	if !ok {
+		return nil, nil, fmt.Errorf("not found: %s", url)
+	}
+	return ioutil.NopCloser(bytes.NewReader(b)), http.Header{"Content-Type": {http.DetectContentType(b)}}, nil
+}
+
+func TestOpenGraph_ImageFetcher(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	Expect(t, png.Encode(buf, image.NewRGBA(image.Rect(0, 0, 4, 3)))).ToBe(nil)
+	og := New("https://example.com/")
+	og.Policy.ImageFetcher = stubImageFetcher{"https://example.com/a.png": buf.Bytes()}
+	og.Image = []*OGImage{{URL: "/a.png"}, {URL: "/missing.png"}}
+
+	Expect(t, og.ResolveImageDimensions(context.Background())).ToBe(nil)
+	Expect(t, og.Image[0].Width).ToBe(4)
+	Expect(t, og.Image[0].Height).ToBe(3)
+
+	images, err := og.ValidateImages(context.Background())
+	Expect(t, err).ToBe(nil)
+	Expect(t, len(images)).ToBe(1)
+	Expect(t, images[0].URL).ToBe("/a.png")
+}
+
+func TestFetchWithTimeout(t *testing.T) {
+	s := dummySlowServer(time.Millisecond * 300)
+	defer s.Close()
+
+	_, err := FetchWithTimeout(time.Millisecond*500, s.URL)
+	Expect(t, err).ToBe(nil)
+
+	_, err = FetchWithTimeout(time.Millisecond*100, s.URL)
+	Expect(t, err).Match(context.DeadlineExceeded.Error())
+
+	When(t, "Policy.Timeout is shorter than context", func(t *testing.T) {
+		og := New(s.URL)
+		og.Policy.Timeout = time.Millisecond * 100
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		err := og.Fetch(ctx)
+		Expect(t, err).Match(context.DeadlineExceeded.Error())
+	})
+}
+
+func dummyServer(id int) *httptest.Server {
+	marmoset.LoadViews("./test/html")
+	r := marmoset.NewRouter()
+	r.GET("/", func(w http.ResponseWriter, r *http.Request) {
+		marmoset.Render(w).HTML(fmt.Sprintf("%02d", id), nil)
+	})
+	r.GET("/case/01", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	return httptest.NewServer(r)
+}
+
+func dummyRawServer(id int, contentType string) *httptest.Server {
+	var h = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadFile(fmt.Sprintf("./test/html/%02d.html", id))
+		w.Header().Set("Content-Type", contentType)
+		w.Write(b)
+	})
+	return httptest.NewServer(h)
+}
+
+func dummyImageServer() *httptest.Server {
+	var h = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/images/ok.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte("ok"))
+		case "/images/ok.jpg":
+			w.Header().Set("Content-Type", "image/jpeg")
+		case "/images/3x2.png":
+			w.Header().Set("Content-Type", "image/png")
+			png.Encode(w, image.NewRGBA(image.Rect(0, 0, 3, 2)))
+		case "/images/2x1.webp":
+			w.Header().Set("Content-Type", "image/webp")
+			w.Write(webp2x1)
+		case "/images/1200x630.svg":
+			w.Header().Set("Content-Type", "image/svg+xml")
+			w.Write([]byte(`<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg" width="1200px" height="630"></svg>`))
+		case "/images/viewbox.svg":
+			w.Header().Set("Content-Type", "image/svg+xml")
+			w.Write([]byte(`<svg xmlns="http://www.w3.org/2000/svg" width="100%" viewBox="0 0 600.4,315"></svg>`))
+		case "/images/unknown.svg":
+			w.Header().Set("Content-Type", "image/svg+xml")
+			w.Write([]byte(`<svg xmlns="http://www.w3.org/2000/svg" width="100%"></svg>`))
+		case "/images/1x1.avif":
+			w.Header().Set("Content-Type", "image/avif")
+			w.Write([]byte("\x00\x00\x00\x1cftypavif"))
+		case "/images/html.png":
+			w.Header().Set("Content-Type", "text/html")
+		case "/images/favicon.ico":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Write([]byte("\x00\x00\x01\x00\x01\x00\x01\x01"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	return httptest.NewServer(h)
+}
+
+// webp2x1 is a lossless 2x1 WebP image.
+var webp2x1 = []byte{
+	0x52, 0x49, 0x46, 0x46, 0x1a, 0x00, 0x00, 0x00, 0x57, 0x45, 0x42, 0x50,
+	0x56, 0x50, 0x38, 0x4c, 0x0d, 0x00, 0x00, 0x00, 0x2f, 0x01, 0x00, 0x00,
+	0x00, 0x07, 0x10, 0xfd, 0x8f, 0xfe, 0x07, 0x22, 0xa2, 0xff, 0x01, 0x00,
 }
 
 func dummySlowServer(d time.Duration) *httptest.Server {
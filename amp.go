@@ -0,0 +1,14 @@
+package opengraph
+
+import "golang.org/x/net/html"
+
+// isAMP returns if given <html> element has "amp" or "⚡" attribute.
+// Note that <link rel="amphtml"> is put on the non-AMP page, so it doesn't make the page AMP.
+func isAMP(n *html.Node) bool {
+	for _, attr := range n.Attr {
+		if attr.Key == "amp" || attr.Key == "⚡" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package opengraph
+
+import (
+	"bytes"
+	"sync"
+)
+
+// BatchParse parses given documents concurrently with the same policy, and returns results in the same order.
+// Each result has its own copy of policy, and its error in og.Error if failed.
+// Concurrency is 4 if not positive. URLs stay relative since the documents have no URL,
+// so start from DefaultPolicy to get the same settings as New.
+func BatchParse(docs [][]byte, policy Policy, concurrency int) []*OpenGraph {
+	if concurrency <= 0 {
+		concurrency = defaultFetchAllConcurrency
+	}
+	results := make([]*OpenGraph, len(docs))
+	jobs := make(chan int)
+	wg := new(sync.WaitGroup)
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				og := New("")
+				og.Policy = policy.clone()
+				if err := og.Parse(bytes.NewReader(docs[i])); err != nil {
+					og.Error = err
+				}
+				results[i] = og
+			}
+		}()
+	}
+	for i := range docs {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,84 @@
+package opengraph
+
+import (
+	"sync"
+	"time"
+)
+
+// Cache stores fetched OpenGraph by URL, specified by Policy.Cache.
+type Cache interface {
+	// Get returns cached OpenGraph of given URL, if any and not expired.
+	Get(url string) (*OpenGraph, bool)
+	// Set stores OpenGraph of given URL for ttl.
+	// ttl is og:ttl if specified, otherwise 0 which lets the implementation decide.
+	Set(url string, og *OpenGraph, ttl time.Duration)
+}
+
+// MemoryCache is an in-memory Cache safe for concurrent use.
+type MemoryCache struct {
+	// DefaultTTL is used when ttl is not specified, 0 means no expiration.
+	DefaultTTL time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	og      *OpenGraph
+	expires time.Time
+}
+
+// NewMemoryCache creates MemoryCache with DefaultTTL.
+func NewMemoryCache(defaultTTL time.Duration) *MemoryCache {
+	return &MemoryCache{DefaultTTL: defaultTTL, entries: map[string]cacheEntry{}}
+}
+
+// Get returns cached OpenGraph of given URL, if any and not expired.
+func (c *MemoryCache) Get(url string) (*OpenGraph, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[url]
+	if !ok {
+		return nil, false
+	}
+	if !e.expires.IsZero() && time.Now().After(e.expires) {
+		delete(c.entries, url)
+		return nil, false
+	}
+	return e.og, true
+}
+
+// Set stores OpenGraph of given URL for ttl, or DefaultTTL if ttl is not positive.
+func (c *MemoryCache) Set(url string, og *OpenGraph, ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = c.DefaultTTL
+	}
+	e := cacheEntry{og: og}
+	if ttl > 0 {
+		e.expires = time.Now().Add(ttl)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = map[string]cacheEntry{}
+	}
+	c.entries[url] = e
+}
+
+// fromCache fills og with a deep copy of the cached one, keeping Policy and HTTPClient of og,
+// so that changes of og don't affect the cache.
+func (og *OpenGraph) fromCache() bool {
+	cached, ok := og.Policy.Cache.Get(og.URL.Source)
+	if !ok || cached == nil {
+		return false
+	}
+	policy, client, source := og.Policy, og.HTTPClient, og.source
+	*og = *cached.clone()
+	og.Policy, og.HTTPClient, og.source = policy, client, source
+	return true
+}
+
+// toCache stores a deep copy of og for og:ttl.
+func (og *OpenGraph) toCache(url string) {
+	og.Policy.Cache.Set(url, og.clone(), og.ttl)
+}
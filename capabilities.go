@@ -0,0 +1,39 @@
+package opengraph
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Capabilities reports which kinds of metadata a document uses, e.g. for analytics of adoption.
+// It's computed while parsing from what is parsed anyway.
+type Capabilities struct {
+	// HasStructuredImages tells og:image has structured properties such as og:image:width.
+	HasStructuredImages bool
+	// HasVerticalType tells og:type is a vertical such as article or music.* defined by ogp.me, or product.
+	HasVerticalType bool
+	// HasTwitterCard tells the document declares twitter:card.
+	HasTwitterCard bool
+	// HasJSONLD tells the document has <script type="application/ld+json">.
+	HasJSONLD bool
+}
+
+// isJSONLD returns if given <script> is JSON-LD.
+func isJSONLD(n *html.Node) bool {
+	for _, attr := range n.Attr {
+		if attr.Key == "type" {
+			return strings.EqualFold(strings.TrimSpace(attr.Val), "application/ld+json")
+		}
+	}
+	return false
+}
+
+// hasVerticalType returns if og:type is in a namespace of verticals, e.g. "music.song" or "product.item".
+func (og *OpenGraph) hasVerticalType() bool {
+	switch og.typeNamespace() {
+	case "article", "book", "product", "profile", "music", "video":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package opengraph
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"mime"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// prescanSize is how many leading bytes are inspected to detect charset,
+// same as the HTML spec's prescan limit.
+const prescanSize = 1024
+
+// decode wraps given body with a reader which transcodes it to UTF-8.
+// The charset is determined by Content-Type header, BOM and <meta> in this order,
+// unless Policy.PreferMetaCharset is set, which lets <meta> override the header.
+// If none of them declares charset, the body is regarded as UTF-8 rather than guessed,
+// since the guess falls back to windows-1252 when the leading bytes are ASCII.
+func (og *OpenGraph) decode(body io.Reader, contentType string) io.Reader {
+	r := bufio.NewReaderSize(body, prescanSize)
+	head, _ := r.Peek(prescanSize)
+	meta, _ := charset.Lookup(metaCharset(head))
+	if og.Policy.PreferMetaCharset && meta != nil {
+		return transform.NewReader(r, meta.NewDecoder())
+	}
+	e, _, certain := charset.DetermineEncoding(head, contentType)
+	if !certain && meta == nil {
+		return r
+	}
+	return transform.NewReader(r, e.NewDecoder())
+}
+
+// stripBOM skips a leading UTF-8 BOM of given body,
+// or transcodes it to UTF-8 if it starts with a UTF-16 BOM.
+func stripBOM(body io.Reader) io.Reader {
+	r := bufio.NewReader(body)
+	head, _ := r.Peek(3)
+	switch {
+	case bytes.HasPrefix(head, []byte{0xEF, 0xBB, 0xBF}):
+		r.Discard(3)
+	case bytes.HasPrefix(head, []byte{0xFE, 0xFF}):
+		return transform.NewReader(r, unicode.UTF16(unicode.BigEndian, unicode.ExpectBOM).NewDecoder())
+	case bytes.HasPrefix(head, []byte{0xFF, 0xFE}):
+		return transform.NewReader(r, unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM).NewDecoder())
+	}
+	return r
+}
+
+// metaCharset finds charset declared by <meta> in given leading bytes of document.
+func metaCharset(head []byte) string {
+	z := html.NewTokenizer(bytes.NewReader(head))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return ""
+		case html.StartTagToken, html.SelfClosingTagToken:
+			t := z.Token()
+			if t.Data != HTMLMetaTag {
+				continue
+			}
+			if cs := charsetFromAttrs(t.Attr); cs != "" {
+				return cs
+			}
+		}
+	}
+}
+
+// CharsetFromMeta returns charset declared by given <meta> node,
+// either <meta charset="..."> or <meta http-equiv="Content-Type" content="...; charset=...">.
+// It returns empty if the node is not <meta> or declares no charset.
+func CharsetFromMeta(n *html.Node) string {
+	if n == nil || n.Type != html.ElementNode || n.Data != HTMLMetaTag {
+		return ""
+	}
+	return charsetFromAttrs(n.Attr)
+}
+
+// charsetFromAttrs returns charset declared by attributes of <meta>,
+// either <meta charset="..."> or <meta http-equiv="Content-Type" content="...; charset=...">.
+func charsetFromAttrs(attrs []html.Attribute) string {
+	var httpEquiv, content string
+	for _, attr := range attrs {
+		switch strings.ToLower(attr.Key) {
+		case "charset":
+			return strings.TrimSpace(attr.Val)
+		case "http-equiv":
+			httpEquiv = attr.Val
+		case "content":
+			content = attr.Val
+		}
+	}
+	if !strings.EqualFold(httpEquiv, "content-type") {
+		return ""
+	}
+	_, params, err := mime.ParseMediaType(content)
+	if err != nil {
+		return ""
+	}
+	return params["charset"]
+}
@@ -0,0 +1,152 @@
+package opengraph
+
+// clone returns a deep copy of og, so that neither of them is affected by changes of the other.
+// Node is shared since parsed documents are not supposed to be modified,
+// and source is not copied as it belongs to the caller of FetchRaw.
+func (og *OpenGraph) clone() *OpenGraph {
+	c := *og
+	c.Policy = og.Policy.clone()
+	if og.URL.URL != nil {
+		u := *og.URL.URL
+		c.URL.URL = &u
+	}
+	if og.Image != nil {
+		c.Image = make([]*OGImage, len(og.Image))
+		for i, img := range og.Image {
+			c.Image[i] = img.clone()
+		}
+	}
+	if og.Video != nil {
+		c.Video = make([]*OGVideo, len(og.Video))
+		for i, video := range og.Video {
+			if video != nil {
+				v := *video
+				c.Video[i] = &v
+			}
+		}
+	}
+	if og.Audio != nil {
+		c.Audio = make([]*OGAudio, len(og.Audio))
+		for i, audio := range og.Audio {
+			if audio != nil {
+				a := *audio
+				c.Audio[i] = &a
+			}
+		}
+	}
+	if og.Article != nil {
+		a := *og.Article
+		a.Author = copyStrings(a.Author)
+		a.Tag = copyStrings(a.Tag)
+		c.Article = &a
+	}
+	if og.VideoObject != nil {
+		v := *og.VideoObject
+		if v.Actors != nil {
+			v.Actors = append([]OGActor{}, v.Actors...)
+		}
+		v.Directors = copyStrings(v.Directors)
+		v.Writers = copyStrings(v.Writers)
+		v.Tags = copyStrings(v.Tags)
+		c.VideoObject = &v
+	}
+	if og.Product != nil {
+		p := *og.Product
+		c.Product = &p
+	}
+	c.LocaleAlt = copyStrings(og.LocaleAlt)
+	if og.Restrictions != nil {
+		r := *og.Restrictions
+		r.CountryAllowed = copyStrings(r.CountryAllowed)
+		r.CountryDisallowed = copyStrings(r.CountryDisallowed)
+		r.Content = copyStrings(r.Content)
+		c.Restrictions = &r
+	}
+	if og.Facebook != nil {
+		f := *og.Facebook
+		f.Admins = copyStrings(f.Admins)
+		c.Facebook = &f
+	}
+	if og.Robots != nil {
+		r := *og.Robots
+		r.Directives = copyStrings(r.Directives)
+		c.Robots = &r
+	}
+	if og.Mobile != nil {
+		m := *og.Mobile
+		c.Mobile = &m
+	}
+	if og.Feeds != nil {
+		c.Feeds = append([]Feed{}, og.Feeds...)
+	}
+	c.Links = copyStrings(og.Links)
+	if og.Raw != nil {
+		c.Raw = make(map[string][]string, len(og.Raw))
+		for k, v := range og.Raw {
+			c.Raw[k] = copyStrings(v)
+		}
+	}
+	if og.Warnings != nil {
+		c.Warnings = append([]Warning{}, og.Warnings...)
+	}
+	c.Provenance = copyStringMap(og.Provenance)
+	if og.Stats != nil {
+		s := *og.Stats
+		c.Stats = &s
+	}
+	c.RedirectChain = copyStrings(og.RedirectChain)
+
+	c.urls = copyStrings(og.urls)
+	if og.alternates != nil {
+		c.alternates = make([]*Link, len(og.alternates))
+		for i, link := range og.alternates {
+			l := *link
+			c.alternates[i] = &l
+		}
+	}
+	c.links = copyBoolMap(og.links)
+	c.microdata = copyStringMap(og.microdata)
+	c.assigned = copyBoolMap(og.assigned)
+	c.assignedInBody = copyBoolMap(og.assignedInBody)
+	c.bodyImage = og.bodyImage.clone()
+	c.authors = copyStrings(og.authors)
+	c.prefixes = copyStringMap(og.prefixes)
+	c.dropped = copyBoolMap(og.dropped)
+	c.exceeded = copyBoolMap(og.exceeded)
+	c.pendingImage = og.pendingImage.clone()
+	c.source = nil
+	return &c
+}
+
+func (img *OGImage) clone() *OGImage {
+	if img == nil {
+		return nil
+	}
+	c := *img
+	if img.Renditions != nil {
+		c.Renditions = append([]Dimension{}, img.Renditions...)
+	}
+	return &c
+}
+
+func copyStringMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	c := make(map[string]string, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+func copyBoolMap(m map[string]bool) map[string]bool {
+	if m == nil {
+		return nil
+	}
+	c := make(map[string]bool, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,30 @@
+package opengraph
+
+import "time"
+
+// dateLayouts are layouts of ISO 8601 to parse date properties, in order.
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// parseDate parses date property value with dateLayouts, then Policy.DateLayouts.
+func (og *OpenGraph) parseDate(value string) (time.Time, bool) {
+	for _, layouts := range [][]string{dateLayouts, og.Policy.DateLayouts} {
+		if t, ok := parseDateWith(layouts, value); ok {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func parseDateWith(layouts []string, value string) (time.Time, bool) {
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,61 @@
+package opengraph
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Determiner represents "og:determiner" value, the word before og:title in a sentence.
+type Determiner string
+
+// Determiners defined by ogp.me. DeterminerNone is the default.
+const (
+	DeterminerNone Determiner = ""
+	DeterminerA    Determiner = "a"
+	DeterminerAn   Determiner = "an"
+	DeterminerThe  Determiner = "the"
+	DeterminerAuto Determiner = "auto"
+)
+
+// ParseDeterminer returns given og:determiner as Determiner, case-insensitively,
+// or an error if it's not defined by ogp.me.
+func ParseDeterminer(s string) (Determiner, error) {
+	d := Determiner(strings.ToLower(strings.TrimSpace(s)))
+	if !d.Valid() {
+		return "", fmt.Errorf("og:determiner is unknown: %s", s)
+	}
+	return d, nil
+}
+
+// Valid returns if d is defined by ogp.me.
+func (d Determiner) Valid() bool {
+	switch d {
+	case DeterminerNone, DeterminerA, DeterminerAn, DeterminerThe, DeterminerAuto:
+		return true
+	}
+	return false
+}
+
+func (d Determiner) String() string {
+	return string(d)
+}
+
+// TitleWithDeterminer returns og:title preceded by og:determiner, e.g. "the Great Gatsby".
+// "auto" chooses "a" or "an" by the first letter of the title, and empty determiner adds nothing.
+// Title itself is not modified.
+func (og *OpenGraph) TitleWithDeterminer() string {
+	if og.Title == "" {
+		return ""
+	}
+	determiner := og.Determiner
+	if determiner == DeterminerAuto {
+		determiner = DeterminerA
+		if strings.ContainsAny(strings.ToLower(og.Title[:1]), "aeiou") {
+			determiner = DeterminerAn
+		}
+	}
+	if determiner == DeterminerNone {
+		return og.Title
+	}
+	return string(determiner) + " " + og.Title
+}
@@ -0,0 +1,125 @@
+package opengraph
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Difference represents a field-level difference between two OpenGraph.
+type Difference struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// Diff compares og with other field by field, and returns the differences.
+// Structures such as Image are paired by URL and compared element-wise, e.g. `Image["/a.png"].Width`.
+// Unpaired ones are reported as removed or added, e.g. {Field: `Image["/a.png"]`, Old: "/a.png", New: ""},
+// and elements of the same URL are paired in order.
+// Policy and utility fields such as HTTPClient are not compared.
+// Values of other packages, such as time.Time, are compared by its string representation.
+func (og *OpenGraph) Diff(other *OpenGraph) []Difference {
+	if other == nil {
+		other = &OpenGraph{}
+	}
+	diffs := []Difference{}
+	a, b := reflect.ValueOf(og).Elem(), reflect.ValueOf(other).Elem()
+	for i := 0; i < a.NumField(); i++ {
+		f := a.Type().Field(i)
+		if f.PkgPath != "" || f.Tag.Get("json") == "-" || f.Name == "Policy" {
+			continue
+		}
+		if f.Name == "URL" {
+			diffs = appendDiff(diffs, "URL.Value", og.URL.Value, other.URL.Value)
+			continue
+		}
+		diffs = diffValue(diffs, f.Name, a.Field(i), b.Field(i))
+	}
+	return diffs
+}
+
+func diffValue(diffs []Difference, name string, a, b reflect.Value) []Difference {
+	switch {
+	case a.Kind() == reflect.Ptr:
+		return diffValue(diffs, name, indirect(a), indirect(b))
+	case a.Kind() == reflect.Struct && a.Type().PkgPath() == reflect.TypeOf(OpenGraph{}).PkgPath():
+		for i := 0; i < a.NumField(); i++ {
+			diffs = diffValue(diffs, name+"."+a.Type().Field(i).Name, a.Field(i), b.Field(i))
+		}
+		return diffs
+	case a.Kind() == reflect.Slice && a.Type().Elem().Kind() == reflect.Ptr && hasURLField(a.Type().Elem().Elem()):
+		return diffByURL(diffs, name, a, b)
+	case a.Kind() == reflect.Slice && a.Type().Elem().Kind() == reflect.Ptr:
+		for i := 0; i < a.Len() || i < b.Len(); i++ {
+			diffs = diffValue(diffs, fmt.Sprintf("%s[%d]", name, i), index(a, i), index(b, i))
+		}
+		return diffs
+	default:
+		return appendDiff(diffs, name, fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()))
+	}
+}
+
+func appendDiff(diffs []Difference, name, a, b string) []Difference {
+	if a == b {
+		return diffs
+	}
+	return append(diffs, Difference{Field: name, Old: a, New: b})
+}
+
+// indirect returns the value pointed by v, or zero value if v is nil.
+func indirect(v reflect.Value) reflect.Value {
+	if v.IsNil() {
+		return reflect.Zero(v.Type().Elem())
+	}
+	return v.Elem()
+}
+
+// index returns i-th element of v, or nil pointer if out of range.
+func index(v reflect.Value, i int) reflect.Value {
+	if i >= v.Len() {
+		return reflect.Zero(v.Type().Elem())
+	}
+	return v.Index(i)
+}
+
+// hasURLField returns if t is a struct with URL string field, such as OGImage.
+func hasURLField(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	f, ok := t.FieldByName("URL")
+	return ok && f.Type.Kind() == reflect.String
+}
+
+// diffByURL compares elements of a and b paired by URL, in order of a and then added ones of b.
+func diffByURL(diffs []Difference, name string, a, b reflect.Value) []Difference {
+	paired := make([]bool, b.Len())
+	for i := 0; i < a.Len(); i++ {
+		u := elemURL(a.Index(i))
+		key := fmt.Sprintf("%s[%q]", name, u)
+		j := 0
+		for ; j < b.Len(); j++ {
+			if !paired[j] && elemURL(b.Index(j)) == u {
+				break
+			}
+		}
+		if j == b.Len() {
+			diffs = append(diffs, Difference{Field: key, Old: u})
+			continue
+		}
+		paired[j] = true
+		diffs = diffValue(diffs, key, a.Index(i), b.Index(j))
+	}
+	for j := 0; j < b.Len(); j++ {
+		if !paired[j] {
+			u := elemURL(b.Index(j))
+			diffs = append(diffs, Difference{Field: fmt.Sprintf("%s[%q]", name, u), New: u})
+		}
+	}
+	return diffs
+}
+
+// elemURL returns URL field of the struct pointed by v, or empty if v is nil.
+func elemURL(v reflect.Value) string {
+	return indirect(v).FieldByName("URL").String()
+}
@@ -0,0 +1,70 @@
+package opengraph
+
+import (
+	"context"
+	"image"
+	"net/url"
+	"strconv"
+	"strings"
+
+	// Decoders to resolve dimensions of og:image.
+	// AVIF is not supported yet, and such images are skipped.
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	_ "golang.org/x/image/webp"
+)
+
+// ResolveImageDimensions fetches each og.Image without og:image:width or og:image:height concurrently,
+// and fills Width and Height by decoding its header, or reading the root element of SVG if Policy.ResolveSVGDimensions.
+// Images which can't be fetched or are in unsupported formats are left as they are.
+func (og *OpenGraph) ResolveImageDimensions(ctx context.Context) error {
+	return og.eachImage(ctx, func(i int, img *OGImage) {
+		if img.Width != 0 && img.Height != 0 {
+			return
+		}
+		if cfg, ok := og.decodeImageConfig(ctx, og.abs(img.URL)); ok {
+			img.Width, img.Height = cfg.Width, cfg.Height
+		}
+	})
+}
+
+func (og *OpenGraph) decodeImageConfig(ctx context.Context, rawurl string) (image.Config, bool) {
+	body, header, err := og.imageFetcher().Fetch(ctx, rawurl)
+	if err != nil {
+		return image.Config{}, false
+	}
+	defer body.Close()
+	if og.Policy.ResolveSVGDimensions && strings.HasPrefix(header.Get("Content-Type"), "image/svg+xml") {
+		width, height, ok := svgDimensions(og.limit(body))
+		return image.Config{Width: width, Height: height}, ok
+	}
+	cfg, _, err := image.DecodeConfig(og.limit(body))
+	if err != nil {
+		return image.Config{}, false
+	}
+	return cfg, true
+}
+
+// DimensionsFromURL guesses width and height from query parameters of the image URL,
+// such as "?w=1200&h=630" or "?width=1200&height=630" used by some CDNs.
+// It's heuristic and 0 is returned for unknown ones, so it's never applied automatically.
+func (img *OGImage) DimensionsFromURL() (width, height int) {
+	u, err := url.Parse(img.URL)
+	if err != nil {
+		return 0, 0
+	}
+	q := u.Query()
+	return queryInt(q, "w", "width"), queryInt(q, "h", "height")
+}
+
+// queryInt returns the first positive integer of given keys in q, or 0.
+func queryInt(q url.Values, keys ...string) int {
+	for _, key := range keys {
+		if n, err := strconv.Atoi(q.Get(key)); err == nil && n > 0 {
+			return n
+		}
+	}
+	return 0
+}
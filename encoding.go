@@ -0,0 +1,26 @@
+package opengraph
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// decompress wraps response body according to its Content-Encoding.
+// gzip is usually decompressed by http.Transport, except when the request specified Accept-Encoding.
+func decompress(res *http.Response) (io.Reader, error) {
+	switch enc := strings.ToLower(strings.TrimSpace(res.Header.Get("Content-Encoding"))); enc {
+	case "", "identity":
+		return res.Body, nil
+	case "br":
+		return brotli.NewReader(res.Body), nil
+	case "gzip":
+		return gzip.NewReader(res.Body)
+	default:
+		return nil, fmt.Errorf("unsupported Content-Encoding: %s", enc)
+	}
+}
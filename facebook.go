@@ -0,0 +1,7 @@
+package opengraph
+
+// Facebook represents Facebook specific "fb:*" properties.
+type Facebook struct {
+	AppID  string
+	Admins []string
+}
@@ -0,0 +1,112 @@
+package opengraph
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// FetchFaviconURL fetches given page only to find its favicon, and returns it resolved to absolute URL.
+// The page is parsed only until the first favicon <link> or <body>, and falls back to "/favicon.ico".
+// Use OpenGraph.FetchFaviconURL to configure Policy beforehand.
+func FetchFaviconURL(ctx context.Context, pageURL string) (string, error) {
+	og := New(pageURL)
+	og.Policy.TrustedTags = []string{HTMLLinkTag}
+	og.Policy.StopAtBody = true
+	return og.FetchFaviconURL(ctx)
+}
+
+// FetchFaviconURL fetches og.URL like Fetch but only until the first favicon <link>,
+// and returns the favicon resolved to absolute URL.
+func (og *OpenGraph) FetchFaviconURL(ctx context.Context) (string, error) {
+	og.faviconOnly = true
+	defer func() { og.faviconOnly = false }()
+	if err := og.Fetch(ctx); err != nil {
+		return "", err
+	}
+	return og.abs(og.Favicon), nil
+}
+
+// FetchFavicon fetches og.Favicon, resolved to absolute URL, with og.HTTPClient,
+// and returns its bytes and content type. "/favicon.ico" is used if og.Favicon is empty.
+// The body is limited by Policy.MaxBodyBytes as well as the document.
+func (og *OpenGraph) FetchFavicon(ctx context.Context) ([]byte, string, error) {
+	return og.fetchFavicon(ctx, 0)
+}
+
+// fetchFavicon is FetchFavicon which reads at most max+1 bytes if max is positive,
+// so that callers can tell the favicon exceeds max without reading all of it.
+func (og *OpenGraph) fetchFavicon(ctx context.Context, max int64) ([]byte, string, error) {
+	favicon := og.Favicon
+	if favicon == "" {
+		favicon = "/favicon.ico"
+	}
+	req, err := http.NewRequest("GET", og.abs(favicon), nil)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := og.checkHost(req.URL); err != nil {
+		return nil, "", err
+	}
+	res, err := og.client().Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, "", err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return nil, "", fmt.Errorf("failed to fetch favicon: %s", res.Status)
+	}
+	body := og.limit(res.Body)
+	if max > 0 {
+		body = io.LimitReader(body, max+1)
+	}
+	b, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, "", err
+	}
+	contentType := res.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(b)
+	}
+	return b, contentType, nil
+}
+
+// defaultMaxInlineFaviconBytes is Policy.MaxInlineFaviconBytes set by New.
+const defaultMaxInlineFaviconBytes = 32 << 10
+
+// ErrFaviconTooLarge is returned by InlineFavicon when the favicon exceeds Policy.MaxInlineFaviconBytes.
+var ErrFaviconTooLarge = errors.New("favicon exceeds max inline favicon bytes")
+
+// InlineFavicon fetches the favicon by FetchFavicon, and replaces og.Favicon with its data URI,
+// e.g. "data:image/png;base64,...", so that previews need no more requests.
+// The type is sniffed from content if the declared one is not an image, e.g. application/octet-stream.
+// og.Favicon is left as it is if the favicon is not an image or exceeds Policy.MaxInlineFaviconBytes,
+// which stops reading as soon as exceeded. It does nothing if og.Favicon is already a data URI.
+func (og *OpenGraph) InlineFavicon(ctx context.Context) error {
+	if strings.HasPrefix(strings.ToLower(og.Favicon), "data:") {
+		return nil
+	}
+	max := og.Policy.MaxInlineFaviconBytes
+	b, contentType, err := og.fetchFavicon(ctx, max)
+	if err != nil {
+		return err
+	}
+	if max > 0 && int64(len(b)) > max {
+		return ErrFaviconTooLarge
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || !strings.HasPrefix(mediaType, "image/") {
+		mediaType, _, err = mime.ParseMediaType(http.DetectContentType(b))
+	}
+	if err != nil || !strings.HasPrefix(mediaType, "image/") {
+		return fmt.Errorf("favicon is not an image: %s", contentType)
+	}
+	og.Favicon = "data:" + mediaType + ";base64," + base64.StdEncoding.EncodeToString(b)
+	return nil
+}
@@ -0,0 +1,92 @@
+package opengraph
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// defaultFetchAllConcurrency is the number of workers of FetchAll if not specified.
+const defaultFetchAllConcurrency = 4
+
+// FetchAllOptions specifies how FetchAll fetches URLs.
+type FetchAllOptions struct {
+	// Concurrency is the number of simultaneous fetches, 4 by default.
+	Concurrency int
+	// RequestsPerSecond limits the rate of requests across the whole batch, 0 means unlimited.
+	// Rates over 1e9 are regarded as unlimited too.
+	RequestsPerSecond float64
+}
+
+// FetchAll fetches and parses given URLs concurrently, and returns results in the same order.
+// Each result has its error in og.Error if failed.
+func FetchAll(ctx context.Context, urls []string, opts FetchAllOptions) []*OpenGraph {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultFetchAllConcurrency
+	}
+	limiter := newRateLimiter(opts.RequestsPerSecond)
+	defer limiter.stop()
+
+	results := make([]*OpenGraph, len(urls))
+	jobs := make(chan int)
+	wg := new(sync.WaitGroup)
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				og := New(urls[i])
+				if err := limiter.wait(ctx); err != nil {
+					og.Error = err
+				} else if err := og.Fetch(ctx); err != nil {
+					og.Error = err
+				}
+				results[i] = og
+			}
+		}()
+	}
+	for i := range urls {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+// rateLimiter lets requests pass at fixed intervals.
+type rateLimiter struct {
+	ticker *time.Ticker
+}
+
+// newRateLimiter returns rateLimiter for rps, which doesn't limit if rps is not positive,
+// or too large to be an interval of at least a nanosecond.
+func newRateLimiter(rps float64) *rateLimiter {
+	if !(rps > 0) {
+		return &rateLimiter{}
+	}
+	interval := time.Duration(float64(time.Second) / rps)
+	if interval <= 0 {
+		return &rateLimiter{}
+	}
+	return &rateLimiter{ticker: time.NewTicker(interval)}
+}
+
+// wait blocks until the next request is allowed, or ctx is done.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l.ticker == nil {
+		return ctx.Err()
+	}
+	select {
+	case <-l.ticker.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l *rateLimiter) stop() {
+	if l.ticker != nil {
+		l.ticker.Stop()
+	}
+}
@@ -0,0 +1,72 @@
+package opengraph
+
+import (
+	"sort"
+	"strings"
+)
+
+// structuredRoots are root properties which own structured properties, e.g. "og:image:width".
+var structuredRoots = []string{"og:image", "og:video", "og:audio"}
+
+// FromMap constructs OpenGraph from property-value pairs, such as {"og:title": {"Hello"}},
+// in the same way as parsing <meta property="..." content="..."> tags.
+// Since a map has no order, the i-th value of a structured property like "og:image:width"
+// is applied to the i-th "og:image" (or "og:image:url").
+func FromMap(props map[string][]string) *OpenGraph {
+	og := New("")
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	structured := map[string]bool{}
+	for _, root := range structuredRoots {
+		subs := []string{}
+		for _, k := range keys {
+			if strings.HasPrefix(k, root+":") && k != root+":url" {
+				subs = append(subs, k)
+				structured[k] = true
+			}
+		}
+		values := append(append([]string{}, props[root]...), props[root+":url"]...)
+		structured[root], structured[root+":url"] = true, true
+		for i, v := range values {
+			(&Meta{Property: root, Content: v}).Contribute(og)
+			for _, sub := range subs {
+				if i < len(props[sub]) {
+					(&Meta{Property: sub, Content: props[sub][i]}).Contribute(og)
+				}
+			}
+		}
+	}
+
+	for _, k := range keys {
+		if structured[k] {
+			continue
+		}
+		for _, v := range props[k] {
+			(&Meta{Property: k, Content: v}).Contribute(og)
+		}
+	}
+	og.complete()
+	return og
+}
+
+// Pair is a property-value pair of <meta property="..." content="...">.
+type Pair struct {
+	Property string
+	Content  string
+}
+
+// FromOrderedPairs constructs OpenGraph from property-value pairs in document order,
+// in exactly the same way as parsing <meta> tags of them, e.g. to test that structured properties
+// such as "og:image:width" belong to the latest "og:image".
+func FromOrderedPairs(pairs []Pair) *OpenGraph {
+	og := New("")
+	for _, pair := range pairs {
+		(&Meta{Property: pair.Property, Content: pair.Content}).Contribute(og)
+	}
+	og.complete()
+	return og
+}
@@ -3,8 +3,11 @@ module github.com/dariallab/opengraph
 go 1.14
 
 require (
+	github.com/andybalholm/brotli v1.0.1
 	github.com/otiai10/marmoset v0.4.0
 	github.com/otiai10/mint v1.3.2
 	github.com/urfave/cli v1.22.4
+	golang.org/x/image v0.0.0-20201208152932-35266b937fa6
 	golang.org/x/net v0.0.0-20201010224723-4f7140c49acb
+	golang.org/x/text v0.3.3
 )
@@ -0,0 +1,51 @@
+package opengraph
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// ContentHash returns a hex-encoded SHA-256 hash of the parsed informations, to detect changes between fetches.
+// The canonical serialization is JSON of og with zero Policy and without Raw, which depends on Policy.KeepRaw,
+// in which fields excluded from JSON such as Warnings and Stats are omitted, empty slices at any depth,
+// e.g. Article.Tag, are regarded as nil, and keys of maps are sorted by encoding/json.
+func (og *OpenGraph) ContentHash() string {
+	c := *og
+	c.Policy = Policy{}
+	c.Raw = nil
+	b, err := json.Marshal(c)
+	if err != nil {
+		return ""
+	}
+	var v interface{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		return ""
+	}
+	if b, err = json.Marshal(nilEmptySlices(v)); err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// nilEmptySlices replaces empty arrays in decoded JSON v with null, recursively.
+func nilEmptySlices(v interface{}) interface{} {
+	switch v := v.(type) {
+	case []interface{}:
+		if len(v) == 0 {
+			return nil
+		}
+		for i, e := range v {
+			v[i] = nilEmptySlices(e)
+		}
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = nilEmptySlices(e)
+		}
+	}
+	return v
+}
@@ -0,0 +1,62 @@
+package opengraph
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// HostNotAllowedError is returned by Fetch when the host of requested URL,
+// or of any redirect destination, is disallowed by Policy.AllowedHosts or Policy.BlockedHosts.
+type HostNotAllowedError struct {
+	Host string
+}
+
+func (err *HostNotAllowedError) Error() string {
+	return fmt.Sprintf("host is not allowed: %s", err.Host)
+}
+
+// checkHost returns *HostNotAllowedError if the host of u is disallowed by og.Policy.
+func (og *OpenGraph) checkHost(u *url.URL) error {
+	host := strings.ToLower(u.Hostname())
+	if matchHost(host, og.Policy.BlockedHosts) {
+		return &HostNotAllowedError{Host: host}
+	}
+	if len(og.Policy.AllowedHosts) != 0 && !matchHost(host, og.Policy.AllowedHosts) {
+		return &HostNotAllowedError{Host: host}
+	}
+	return nil
+}
+
+// matchHost reports if host matches any of patterns.
+// A pattern starting with "." matches the domain itself and all of its subdomains.
+func matchHost(host string, patterns []string) bool {
+	for _, p := range patterns {
+		p = strings.ToLower(p)
+		if strings.HasPrefix(p, ".") {
+			if host == p[1:] || strings.HasSuffix(host, p) {
+				return true
+			}
+		} else if host == p {
+			return true
+		}
+	}
+	return false
+}
+
+// client returns og.httpClient(), wrapped to check hosts on every redirect if needed.
+func (og *OpenGraph) client() *http.Client {
+	if len(og.Policy.AllowedHosts) == 0 && len(og.Policy.BlockedHosts) == 0 {
+		return og.httpClient()
+	}
+	c := *og.httpClient()
+	next := c.CheckRedirect
+	c.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if err := og.checkHost(req.URL); err != nil {
+			return err
+		}
+		return checkRedirect(next, req, via)
+	}
+	return &c
+}
@@ -0,0 +1,50 @@
+package opengraph
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Identity returns a normalized URL to deduplicate the same content, picking the first non-empty of
+// <link rel="canonical">, og:url and the fetched URL (the last of RedirectChain if any).
+// The picked URL is normalized as follows:
+//
+//  1. resolved to absolute URL against og.URL
+//  2. scheme and host are lowercased
+//  3. tracking parameters are removed, by Policy.TrackingParams or the default list like "utm_*"
+//  4. remaining query parameters are sorted by key
+//  5. fragment is removed
+//
+// It returns empty if no URL is available.
+func (og *OpenGraph) Identity() string {
+	raw := og.CanonicalURL
+	if raw == "" {
+		raw = og.URL.Value
+	}
+	if raw == "" && len(og.RedirectChain) != 0 {
+		raw = og.RedirectChain[len(og.RedirectChain)-1]
+	}
+	if raw == "" && og.URL.URL != nil {
+		raw = og.URL.String()
+	}
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(og.abs(strings.TrimSpace(raw)))
+	if err != nil {
+		return raw
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.Fragment = ""
+	if u.RawQuery != "" {
+		q := u.Query()
+		for key := range q {
+			if isTrackingParam(key, og.trackingParams()) {
+				q.Del(key)
+			}
+		}
+		u.RawQuery = q.Encode()
+	}
+	return u.String()
+}
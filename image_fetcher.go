@@ -0,0 +1,46 @@
+package opengraph
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ImageFetcher fetches images for ResolveImageDimensions and ValidateImages,
+// specified by Policy.ImageFetcher, e.g. to go through a proxy or a cache.
+type ImageFetcher interface {
+	// Fetch returns the body and header of the image at url.
+	// It should return an error if the image is not available, e.g. 404.
+	Fetch(ctx context.Context, url string) (io.ReadCloser, http.Header, error)
+}
+
+// HTTPImageFetcher is an ImageFetcher with http.Client, used by default with og.HTTPClient.
+type HTTPImageFetcher struct {
+	Client *http.Client
+}
+
+// Fetch sends GET request to url, and returns the body if it responds 2xx.
+func (f *HTTPImageFetcher) Fetch(ctx context.Context, url string) (io.ReadCloser, http.Header, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	res, err := f.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, nil, err
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		res.Body.Close()
+		return nil, nil, fmt.Errorf("failed to fetch image: %s", res.Status)
+	}
+	return res.Body, res.Header, nil
+}
+
+// imageFetcher returns Policy.ImageFetcher, or HTTPImageFetcher with og.HTTPClient.
+func (og *OpenGraph) imageFetcher() ImageFetcher {
+	if og.Policy.ImageFetcher != nil {
+		return og.Policy.ImageFetcher
+	}
+	return &HTTPImageFetcher{Client: og.httpClient()}
+}
@@ -0,0 +1,67 @@
+package opengraph
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// ToJSONLD renders schema.org JSON-LD from OpenGraph informations.
+// The schema type is chosen by og:type, and empty fields are omitted.
+// Image URLs are resolved to absolute URLs even if ToAbsURL is not called.
+func (og *OpenGraph) ToJSONLD() ([]byte, error) {
+	ld := map[string]interface{}{
+		"@context": "https://schema.org",
+		"@type":    og.schemaType(),
+	}
+	if og.Title != "" {
+		ld["headline"] = og.Title
+	}
+	if og.Description != "" {
+		ld["description"] = og.Description
+	}
+	if u := og.canonicalValue(); u != "" {
+		ld["url"] = u
+	}
+	images := []string{}
+	for _, img := range og.Image {
+		if img.URL != "" {
+			images = append(images, og.abs(img.URL))
+		}
+	}
+	if len(images) != 0 {
+		ld["image"] = images
+	}
+	if og.Article != nil {
+		if og.Article.PublishedTime != "" {
+			ld["datePublished"] = og.Article.PublishedTime
+		}
+		if og.Article.ModifiedTime != "" {
+			ld["dateModified"] = og.Article.ModifiedTime
+		}
+	}
+	return json.Marshal(ld)
+}
+
+// schemaType maps og:type to schema.org type.
+func (og *OpenGraph) schemaType() string {
+	switch {
+	case og.Type == "article":
+		return "Article"
+	case og.Type == "profile":
+		return "ProfilePage"
+	case strings.HasPrefix(string(og.Type), "video."):
+		return "VideoObject"
+	case og.Type == "book":
+		return "Book"
+	default:
+		return "WebPage"
+	}
+}
+
+// canonicalValue returns og:url if specified, otherwise the source URL.
+func (og *OpenGraph) canonicalValue() string {
+	if og.URL.Value != "" {
+		return og.URL.Value
+	}
+	return og.URL.Source
+}
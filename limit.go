@@ -0,0 +1,42 @@
+package opengraph
+
+import (
+	"errors"
+	"io"
+	"net/http"
+)
+
+// ErrBodyTooLarge is returned when a response body exceeds Policy.MaxBodyBytes.
+var ErrBodyTooLarge = errors.New("response body exceeds max body bytes")
+
+// limit wraps r to fail with ErrBodyTooLarge after reading Policy.MaxBodyBytes.
+func (og *OpenGraph) limit(r io.Reader) io.Reader {
+	if og.Policy.MaxBodyBytes <= 0 {
+		return r
+	}
+	return &limitedReader{r: io.LimitReader(r, og.Policy.MaxBodyBytes+1), n: og.Policy.MaxBodyBytes}
+}
+
+// checkContentLength rejects the response early with ErrBodyTooLarge
+// if its declared Content-Length exceeds Policy.MaxBodyBytes.
+// Unknown or wrong Content-Length is still caught by limit while reading.
+func (og *OpenGraph) checkContentLength(res *http.Response) error {
+	if og.Policy.MaxBodyBytes > 0 && res.ContentLength > og.Policy.MaxBodyBytes {
+		return ErrBodyTooLarge
+	}
+	return nil
+}
+
+type limitedReader struct {
+	r io.Reader
+	n int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	if l.n < 0 {
+		return n, ErrBodyTooLarge
+	}
+	return n, err
+}
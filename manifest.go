@@ -0,0 +1,82 @@
+package opengraph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// Manifest represents the part of web app manifest used for previews.
+type Manifest struct {
+	Name            string         `json:"name"`
+	ShortName       string         `json:"short_name"`
+	ThemeColor      string         `json:"theme_color"`
+	BackgroundColor string         `json:"background_color"`
+	Display         string         `json:"display"`
+	StartURL        string         `json:"start_url"`
+	Icons           []ManifestIcon `json:"icons"`
+}
+
+// ManifestIcon represents an icon of web app manifest.
+type ManifestIcon struct {
+	Src   string `json:"src"`
+	Sizes string `json:"sizes"`
+	Type  string `json:"type"`
+}
+
+// ErrNoManifest is returned by FetchManifest when the page declares no <link rel="manifest">.
+var ErrNoManifest = errors.New("no manifest is declared")
+
+// FetchManifest fetches og.ManifestURL with og.HTTPClient and decodes it.
+// StartURL and Src of Icons are resolved to absolute URLs against og.ManifestURL.
+// The body is limited by Policy.MaxBodyBytes as well as the document.
+func (og *OpenGraph) FetchManifest(ctx context.Context) (*Manifest, error) {
+	if og.ManifestURL == "" {
+		return nil, ErrNoManifest
+	}
+	req, err := http.NewRequest("GET", og.ManifestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := og.checkHost(req.URL); err != nil {
+		return nil, err
+	}
+	res, err := og.client().Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return nil, fmt.Errorf("failed to fetch manifest: %s", res.Status)
+	}
+	b, err := ioutil.ReadAll(og.limit(res.Body))
+	if err != nil {
+		return nil, err
+	}
+	manifest := new(Manifest)
+	if err := json.Unmarshal(b, manifest); err != nil {
+		return nil, err
+	}
+	base := req.URL
+	manifest.StartURL = resolveReference(base, manifest.StartURL)
+	for i, icon := range manifest.Icons {
+		manifest.Icons[i].Src = resolveReference(base, icon.Src)
+	}
+	return manifest, nil
+}
+
+// resolveReference returns ref resolved against base, or ref as it is if it's empty or invalid.
+func resolveReference(base *url.URL, ref string) string {
+	if ref == "" {
+		return ""
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return base.ResolveReference(u).String()
+}
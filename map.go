@@ -0,0 +1,51 @@
+package opengraph
+
+import "strconv"
+
+// ToMap flattens OpenGraph informations into a map keyed by property names without "og:" prefix,
+// e.g. "title", "image" and "image:width", for template engines.
+// Only the first element of structures such as Image is included, and empty fields are omitted.
+func (og *OpenGraph) ToMap() map[string]string {
+	m := map[string]string{}
+	put := func(key, value string) {
+		if value != "" {
+			m[key] = value
+		}
+	}
+	putInt := func(key string, value int) {
+		if value != 0 {
+			m[key] = strconv.Itoa(value)
+		}
+	}
+	put("title", og.Title)
+	put("type", string(og.Type))
+	put("url", og.URL.Value)
+	put("site_name", og.SiteName)
+	put("description", og.Description)
+	put("determiner", string(og.Determiner))
+	put("locale", string(og.Locale))
+	if len(og.Image) != 0 && og.Image[0] != nil {
+		img := og.Image[0]
+		put("image", img.URL)
+		put("image:secure_url", img.SURL)
+		put("image:type", img.Type)
+		putInt("image:width", img.Width)
+		putInt("image:height", img.Height)
+		put("image:alt", img.Alt)
+	}
+	if len(og.Video) != 0 && og.Video[0] != nil {
+		video := og.Video[0]
+		put("video", video.URL)
+		put("video:secure_url", video.SURL)
+		put("video:type", video.Type)
+		putInt("video:width", video.Width)
+		putInt("video:height", video.Height)
+	}
+	if len(og.Audio) != 0 && og.Audio[0] != nil {
+		audio := og.Audio[0]
+		put("audio", audio.URL)
+		put("audio:secure_url", audio.SURL)
+		put("audio:type", audio.Type)
+	}
+	return m
+}
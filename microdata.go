@@ -0,0 +1,83 @@
+package opengraph
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// ProvenanceMicrodata is a Provenance of fields filled by schema.org microdata.
+const ProvenanceMicrodata = "microdata"
+
+// collectMicrodata records the first value of itemprop="name", "description" and "image".
+// <meta> with both itemprop and property is interpreted only by property,
+// e.g. <meta itemprop="image" property="og:image" content="...">, even if og:image is rejected.
+func (og *OpenGraph) collectMicrodata(n *html.Node) {
+	var prop string
+	for _, attr := range n.Attr {
+		switch attr.Key {
+		case "itemprop":
+			prop = attr.Val
+		case "property":
+			if n.Data == HTMLMetaTag && strings.TrimSpace(attr.Val) != "" {
+				return
+			}
+		}
+	}
+	switch prop {
+	case "name", "description", "image":
+	default:
+		return
+	}
+	if og.microdata[prop] != "" {
+		return
+	}
+	value := strings.TrimSpace(itempropValue(n))
+	if value == "" {
+		return
+	}
+	if og.microdata == nil {
+		og.microdata = map[string]string{}
+	}
+	og.microdata[prop] = value
+}
+
+// itempropValue returns the value of microdata property according to its element.
+func itempropValue(n *html.Node) string {
+	key := ""
+	switch n.Data {
+	case HTMLMetaTag:
+		key = "content"
+	case "img", "audio", "video", "source", "iframe", "embed":
+		key = "src"
+	case HTMLLinkTag, HTMLAnchorTag, "area":
+		key = "href"
+	default:
+		if n.FirstChild != nil && n.FirstChild.Type == html.TextNode {
+			return n.FirstChild.Data
+		}
+		return ""
+	}
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// applyMicrodata fills empty fields with collected microdata as a last resort.
+func (og *OpenGraph) applyMicrodata() {
+	if v := og.microdata["name"]; v != "" && og.Title == "" {
+		og.Title = v
+		og.provide("Title", ProvenanceMicrodata)
+	}
+	if v := og.microdata["description"]; v != "" && og.Description == "" {
+		og.Description = v
+		og.provide("Description", ProvenanceMicrodata)
+	}
+	if v := og.microdata["image"]; v != "" && len(og.Image) == 0 && !og.rejectsImageURL("og:image", v) {
+		og.Image = append(og.Image, &OGImage{URL: og.abs(v)})
+		og.provide("Image", ProvenanceMicrodata)
+	}
+}
@@ -0,0 +1,23 @@
+package opengraph
+
+import "strings"
+
+// Mobile represents hints for mobile devices, which are not OGP.
+type Mobile struct {
+	// Viewport is <meta name="viewport">, e.g. "width=device-width, initial-scale=1".
+	Viewport string
+	// WebAppCapable is if <meta name="apple-mobile-web-app-capable"> is "yes".
+	WebAppCapable bool
+}
+
+func (m *Meta) contributeMobile(og *OpenGraph) {
+	if og.Mobile == nil {
+		og.Mobile = &Mobile{}
+	}
+	switch strings.ToLower(m.Name) {
+	case "viewport":
+		og.Mobile.Viewport = m.Content
+	case "apple-mobile-web-app-capable":
+		og.Mobile.WebAppCapable = strings.EqualFold(strings.TrimSpace(m.Content), "yes")
+	}
+}
@@ -0,0 +1,39 @@
+package opengraph
+
+import (
+	"strings"
+	"time"
+)
+
+// OGArticle represents "article:*" structure, available when og:type is "article".
+// Times are kept as raw strings, and also parsed into Published, Modified and Expiration,
+// which are zero if none of the layouts matches.
+type OGArticle struct {
+	PublishedTime  string
+	ModifiedTime   string
+	ExpirationTime string
+	Published      time.Time
+	Modified       time.Time
+	Expiration     time.Time
+	Author         []string
+	Section        string
+	Tag            []string
+}
+
+// Authors returns article:author and <meta name="author"> merged without duplicates,
+// with article:author first.
+func (og *OpenGraph) Authors() []string {
+	authors := []string{}
+	seen := map[string]bool{}
+	candidates := og.authors
+	if og.Article != nil {
+		candidates = append(append([]string{}, og.Article.Author...), og.authors...)
+	}
+	for _, author := range candidates {
+		if author = strings.TrimSpace(author); author != "" && !seen[author] {
+			seen[author] = true
+			authors = append(authors, author)
+		}
+	}
+	return authors
+}
@@ -1,5 +1,19 @@
 package opengraph
 
+import (
+	"context"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// imagesConcurrency is the max number of simultaneous requests for og.Image.
+const imagesConcurrency = 4
+
 // OGImage represents "og:image" structure.
 type OGImage struct {
 	URL    string
@@ -8,4 +22,246 @@ type OGImage struct {
 	Width  int
 	Height int
 	Alt    string
+
+	UserGenerated bool
+
+	// Renditions are all pairs of og:image:width and og:image:height repeated for the URL,
+	// only if Policy.ImageRenditions is set and there are more than one.
+	// Width and Height are of the first one.
+	Renditions []Dimension
+}
+
+// Dimension represents width and height of an image, 0 if unknown.
+type Dimension struct {
+	Width  int
+	Height int
+}
+
+// addRendition adds width or height of a new rendition, or fills the other one of the last rendition.
+// The first rendition is of img.Width and img.Height.
+func (img *OGImage) addRendition(width, height int) {
+	if width == 0 && height == 0 {
+		return
+	}
+	if len(img.Renditions) == 0 {
+		img.Renditions = []Dimension{{Width: img.Width, Height: img.Height}}
+	}
+	last := &img.Renditions[len(img.Renditions)-1]
+	switch {
+	case width != 0 && last.Width == 0:
+		last.Width = width
+	case height != 0 && last.Height == 0:
+		last.Height = height
+	default:
+		img.Renditions = append(img.Renditions, Dimension{Width: width, Height: height})
+	}
+}
+
+// currentImage returns the image which "og:image:*" properties belong to.
+// Properties preceding the first "og:image" are held until it appears,
+// so that e.g. "og:image:width" before "og:image:url" still belongs to the image.
+// It returns nil if the current image is dropped.
+func (og *OpenGraph) currentImage() *OGImage {
+	if og.dropped["og:image"] {
+		return nil
+	}
+	if len(og.Image) != 0 {
+		return og.Image[len(og.Image)-1]
+	}
+	if og.pendingImage == nil {
+		og.pendingImage = &OGImage{}
+	}
+	return og.pendingImage
+}
+
+// continuesImage returns if given root property is the other spelling of the one which started
+// the current image with the same URL, e.g. "og:image:url" right after "og:image",
+// which describes the same image instead of a new one. It's allowed only once per image.
+func (og *OpenGraph) continuesImage(property, url string) bool {
+	if len(og.Image) == 0 || og.dropped["og:image"] || og.imageRoot == "" || og.imageRoot == property {
+		return false
+	}
+	if og.Image[len(og.Image)-1].URL != url {
+		return false
+	}
+	og.imageRoot = ""
+	return true
+}
+
+// AspectRatio returns width/height of the image, or 0 if either is unknown.
+func (img *OGImage) AspectRatio() float64 {
+	if img.Width <= 0 || img.Height <= 0 {
+		return 0
+	}
+	return float64(img.Width) / float64(img.Height)
+}
+
+// GuessType returns og:image:type if specified, otherwise MIME type guessed by extension of URL path.
+// It returns empty if the path has no image extension, e.g. "/image?id=1".
+func (img *OGImage) GuessType() string {
+	if img.Type != "" {
+		return img.Type
+	}
+	u, err := url.Parse(img.URL)
+	if err != nil {
+		return ""
+	}
+	t := mime.TypeByExtension(strings.ToLower(path.Ext(u.Path)))
+	if !strings.HasPrefix(t, "image/") {
+		return ""
+	}
+	return t
+}
+
+// PrimaryImageAspect returns AspectRatio of the first og:image, or 0 if there is no image.
+func (og *OpenGraph) PrimaryImageAspect() float64 {
+	if len(og.Image) == 0 {
+		return 0
+	}
+	return og.Image[0].AspectRatio()
+}
+
+// SortImagesBySize sorts og.Image by width*height in descending order, in place.
+// Images without dimensions go last, keeping their relative order.
+func (og *OpenGraph) SortImagesBySize() {
+	sort.SliceStable(og.Image, func(i, j int) bool {
+		return og.Image[i].area() > og.Image[j].area()
+	})
+}
+
+// SortedImages returns a copy of og.Image sorted as SortImagesBySize, leaving og.Image untouched.
+func (og *OpenGraph) SortedImages() []*OGImage {
+	images := append([]*OGImage{}, og.Image...)
+	sort.SliceStable(images, func(i, j int) bool {
+		return images[i].area() > images[j].area()
+	})
+	return images
+}
+
+// BestImageForSize returns the smallest og:image of at least minWidth x minHeight,
+// or the largest one if none meets them. Images without dimensions are regarded as the smallest,
+// so call ResolveImageDimensions beforehand if needed.
+// It returns false only if there is no og:image.
+func (og *OpenGraph) BestImageForSize(minWidth, minHeight int) (*OGImage, bool) {
+	var best, largest *OGImage
+	for _, img := range og.Image {
+		if img == nil {
+			continue
+		}
+		if largest == nil || img.area() > largest.area() {
+			largest = img
+		}
+		if img.Width >= minWidth && img.Height >= minHeight && img.area() > 0 {
+			if best == nil || img.area() < best.area() {
+				best = img
+			}
+		}
+	}
+	if best != nil {
+		return best, true
+	}
+	return largest, largest != nil
+}
+
+func (img *OGImage) area() int {
+	if img.Width <= 0 || img.Height <= 0 {
+		return 0
+	}
+	return img.Width * img.Height
+}
+
+// HasInsecureImages returns if any og.Image can only be served over plain http,
+// which causes mixed content on https pages.
+func (og *OpenGraph) HasInsecureImages() bool {
+	for _, img := range og.Image {
+		if !og.isSecureImage(img) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterSecureImages drops og.Image which can only be served over plain http.
+// Images of http URL are kept if they have https og:image:secure_url.
+func (og *OpenGraph) FilterSecureImages() *OpenGraph {
+	images := []*OGImage{}
+	for _, img := range og.Image {
+		if og.isSecureImage(img) {
+			images = append(images, img)
+		}
+	}
+	og.Image = images
+	return og
+}
+
+func (og *OpenGraph) isSecureImage(img *OGImage) bool {
+	return strings.HasPrefix(og.abs(img.URL), "https://") ||
+		(img.SURL != "" && strings.HasPrefix(og.abs(img.SURL), "https://"))
+}
+
+// ValidateImages sends HEAD request to each og.Image concurrently,
+// and returns the subset which responds 2xx with image content type, in original order.
+// Policy.ImageFetcher is used instead of HEAD request if specified.
+// It doesn't modify og.Image, so filter in place by `og.Image, err = og.ValidateImages(ctx)`,
+// which is safe on error since og.Image is returned as it is with the error, e.g. when ctx is done.
+func (og *OpenGraph) ValidateImages(ctx context.Context) ([]*OGImage, error) {
+	ok := make([]bool, len(og.Image))
+	if err := og.eachImage(ctx, func(i int, img *OGImage) {
+		ok[i] = og.isReachableImage(ctx, og.abs(img.URL))
+	}); err != nil {
+		return og.Image, err
+	}
+	images := []*OGImage{}
+	for i, img := range og.Image {
+		if ok[i] {
+			images = append(images, img)
+		}
+	}
+	return images, nil
+}
+
+// eachImage calls f for each og.Image concurrently with bounded concurrency,
+// and waits for all of them. It returns ctx.Err() if ctx is done.
+func (og *OpenGraph) eachImage(ctx context.Context, f func(int, *OGImage)) error {
+	sem := make(chan struct{}, imagesConcurrency)
+	wg := new(sync.WaitGroup)
+	for i, img := range og.Image {
+		wg.Add(1)
+		go func(i int, img *OGImage) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+			f(i, img)
+		}(i, img)
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+func (og *OpenGraph) isReachableImage(ctx context.Context, rawurl string) bool {
+	if og.Policy.ImageFetcher != nil {
+		body, header, err := og.Policy.ImageFetcher.Fetch(ctx, rawurl)
+		if err != nil {
+			return false
+		}
+		body.Close()
+		return strings.HasPrefix(header.Get("Content-Type"), "image/")
+	}
+	req, err := http.NewRequest("HEAD", rawurl, nil)
+	if err != nil {
+		return false
+	}
+	res, err := og.httpClient().Do(req.WithContext(ctx))
+	if err != nil {
+		return false
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return false
+	}
+	return strings.HasPrefix(res.Header.Get("Content-Type"), "image/")
 }
@@ -0,0 +1,161 @@
+package opengraph
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Locale represents "og:locale" value such as "en_US".
+// Both underscore and hyphen are accepted as a separator.
+type Locale string
+
+// ParseLocale returns given og:locale as Locale, or an error if it doesn't look like "en_US" or "en".
+func ParseLocale(s string) (Locale, error) {
+	l := Locale(strings.TrimSpace(s))
+	if !l.Valid() {
+		return "", fmt.Errorf("og:locale is invalid: %s", s)
+	}
+	return l, nil
+}
+
+// Valid returns if l looks like a locale, e.g. "en_US", "en-US" or "en".
+func (l Locale) Valid() bool {
+	_, _, ok := l.split()
+	return ok
+}
+
+func (l Locale) String() string {
+	return string(l)
+}
+
+// Language returns lower-cased language part of the locale, e.g. "en" of "en_US",
+// or empty if the locale is invalid.
+func (l Locale) Language() string {
+	lang, _, ok := l.split()
+	if !ok {
+		return ""
+	}
+	return lang
+}
+
+// Region returns upper-cased region part of the locale, e.g. "US" of "en_US",
+// or empty if the locale is invalid or has no region.
+func (l Locale) Region() string {
+	_, region, ok := l.split()
+	if !ok {
+		return ""
+	}
+	return region
+}
+
+func (l Locale) split() (lang, region string, ok bool) {
+	parts := strings.FieldsFunc(string(l), func(r rune) bool { return r == '_' || r == '-' })
+	if len(parts) == 0 || len(parts) > 2 || strings.Count(string(l), "_")+strings.Count(string(l), "-") != len(parts)-1 {
+		return "", "", false
+	}
+	if !isAlpha(parts[0]) || len(parts[0]) < 2 || len(parts[0]) > 3 {
+		return "", "", false
+	}
+	if len(parts) == 1 {
+		return strings.ToLower(parts[0]), "", true
+	}
+	if !(len(parts[1]) == 2 && isAlpha(parts[1])) && !(len(parts[1]) == 3 && isDigit(parts[1])) {
+		return "", "", false
+	}
+	return strings.ToLower(parts[0]), strings.ToUpper(parts[1]), true
+}
+
+func isAlpha(s string) bool {
+	for _, r := range s {
+		if !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigit(s string) bool {
+	for _, r := range s {
+		if !('0' <= r && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// tag returns the locale as BCP 47 language tag, e.g. "en-US" of "en_US".
+func (l Locale) tag() string {
+	lang, region, ok := l.split()
+	if !ok || region == "" {
+		return lang
+	}
+	return lang + "-" + region
+}
+
+// acceptLanguage returns Accept-Language header value preferring the locale.
+func (l Locale) acceptLanguage() string {
+	if l.Region() == "" {
+		return l.tag()
+	}
+	return l.tag() + "," + l.Language() + ";q=0.9"
+}
+
+// choosePreferredAlternate sets og.PreferredAlternate to the alternate link
+// whose hreflang matches Policy.PreferredLocale, or to the one of the same language.
+func (og *OpenGraph) choosePreferredAlternate() {
+	preferred := Locale(og.Policy.PreferredLocale)
+	if preferred.Language() == "" {
+		return
+	}
+	for _, link := range og.alternates {
+		if strings.EqualFold(Locale(link.Hreflang).tag(), preferred.tag()) {
+			og.PreferredAlternate = link.Href
+			return
+		}
+	}
+	for _, link := range og.alternates {
+		if Locale(link.Hreflang).Language() == preferred.Language() {
+			og.PreferredAlternate = link.Href
+			return
+		}
+	}
+}
+
+// localeAlternates returns og:locale:alternate value as is,
+// or split by commas if Policy.SplitLocaleAlternates is set and every part looks like a locale.
+func (og *OpenGraph) localeAlternates(value string) []string {
+	if !og.Policy.SplitLocaleAlternates || !strings.Contains(value, ",") {
+		return []string{value}
+	}
+	parts := strings.Split(value, ",")
+	for i, part := range parts {
+		parts[i] = strings.TrimSpace(part)
+		if _, _, ok := Locale(parts[i]).split(); !ok {
+			return []string{value}
+		}
+	}
+	return parts
+}
+
+// XDefault is hreflang of the alternate for unmatched languages.
+const XDefault = "x-default"
+
+// AlternatesByLang returns <link rel="alternate" hreflang="..."> as a map from hreflang to absolute href.
+// Keys are normalized like "en-US", and XDefault is kept as a key for "x-default".
+// The first one wins if hreflang is duplicated, and invalid hreflang is ignored.
+func (og *OpenGraph) AlternatesByLang() map[string]string {
+	alternates := map[string]string{}
+	for _, link := range og.alternates {
+		lang := Locale(link.Hreflang).tag()
+		if strings.EqualFold(link.Hreflang, XDefault) {
+			lang = XDefault
+		}
+		if lang == "" || link.Href == "" {
+			continue
+		}
+		if _, ok := alternates[lang]; !ok {
+			alternates[lang] = og.abs(link.Href)
+		}
+	}
+	return alternates
+}
@@ -0,0 +1,12 @@
+package opengraph
+
+// OGProduct represents "product:*" structure, available when og:type is "product".
+// Aliases used by Pinterest rich pins, such as "og:price:amount", are accepted as well.
+type OGProduct struct {
+	PriceAmount    string
+	PriceCurrency  string
+	Availability   string
+	Condition      string
+	Brand          string
+	RetailerItemID string
+}
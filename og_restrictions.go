@@ -0,0 +1,9 @@
+package opengraph
+
+// OGRestrictions represents "og:restrictions:*" structure.
+type OGRestrictions struct {
+	Age               string
+	CountryAllowed    []string
+	CountryDisallowed []string
+	Content           []string
+}
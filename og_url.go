@@ -0,0 +1,68 @@
+package opengraph
+
+import (
+	"net/url"
+	"strings"
+)
+
+// URLPolicy specifies which og:url becomes og.URL.Value when a document has more than one.
+type URLPolicy int
+
+const (
+	// URLPolicyLast takes the last og:url, which is the default.
+	// It takes the first one instead if Policy.FirstWins is set.
+	URLPolicyLast URLPolicy = iota
+	// URLPolicyFirst takes the first og:url.
+	URLPolicyFirst
+	// URLPolicyMatchingHost takes the first og:url whose host is the same as fetched URL,
+	// and falls back to the last one.
+	URLPolicyMatchingHost
+)
+
+// chooseURL decides og.URL.Value from all og:url values seen by parser,
+// unless it's already decided by Policy.ResolveConflict.
+func (og *OpenGraph) chooseURL() {
+	if len(og.urls) < 2 {
+		return
+	}
+	for _, u := range og.urls[1:] {
+		if u != og.urls[0] {
+			og.warn("og:url", "conflicting values %q", og.urls)
+			break
+		}
+	}
+	if og.Policy.ResolveConflict != nil {
+		return
+	}
+	switch og.Policy.URLPolicy {
+	case URLPolicyFirst:
+		og.URL.Value = og.urls[0]
+	case URLPolicyMatchingHost:
+		og.URL.Value = og.urls[len(og.urls)-1]
+		if og.URL.URL == nil {
+			return
+		}
+		for _, raw := range og.urls {
+			if u, err := url.Parse(raw); err == nil && strings.EqualFold(u.Host, og.URL.Host) {
+				og.URL.Value = raw
+				return
+			}
+		}
+	default:
+		if og.Policy.FirstWins {
+			og.URL.Value = og.urls[0]
+		} else {
+			og.URL.Value = og.urls[len(og.urls)-1]
+		}
+	}
+}
+
+// trimURL trims surrounding whitespace of URL, and a pair of matching quotes
+// leaked from templates, e.g. ` "https://example.com/a.png" `.
+func trimURL(raw string) string {
+	v := strings.TrimSpace(raw)
+	if len(v) >= 2 && (v[0] == '"' || v[0] == '\'') && v[len(v)-1] == v[0] {
+		v = strings.TrimSpace(v[1 : len(v)-1])
+	}
+	return v
+}
@@ -1,5 +1,7 @@
 package opengraph
 
+import "time"
+
 // OGVideo represents "og:video" structure.
 type OGVideo struct {
 	URL    string
@@ -8,3 +10,36 @@ type OGVideo struct {
 	Width  int
 	Height int
 }
+
+// continuesVideo returns if given root property is the other spelling of the one which started
+// the current video with the same URL, like continuesImage.
+func (og *OpenGraph) continuesVideo(property, url string) bool {
+	if len(og.Video) == 0 || og.dropped["og:video"] || og.videoRoot == "" || og.videoRoot == property {
+		return false
+	}
+	if og.Video[len(og.Video)-1].URL != url {
+		return false
+	}
+	og.videoRoot = ""
+	return true
+}
+
+// OGVideoObject represents "video:*" structure, available when og:type is "video.*",
+// e.g. "video.movie" or "video.episode".
+type OGVideoObject struct {
+	Actors      []OGActor
+	Directors   []string
+	Writers     []string
+	Duration    int // seconds
+	ReleaseDate time.Time
+	// RawReleaseDate is video:release_date as is, even if ReleaseDate can't be parsed.
+	RawReleaseDate string
+	Tags           []string
+	Series         string
+}
+
+// OGActor represents "video:actor" with its "video:actor:role".
+type OGActor struct {
+	Profile string
+	Role    string
+}
@@ -3,17 +3,20 @@
 package opengraph
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"path"
 	"path/filepath"
-	"sort"
 	"strings"
+	"time"
 
 	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
 )
 
 const (
@@ -23,6 +26,14 @@ const (
 	HTMLMetaTag string = "meta"
 	// HTMLTitleTag is a tag name of <title>
 	HTMLTitleTag string = "title"
+	// HTMLAnchorTag is a tag name of <a>
+	HTMLAnchorTag string = "a"
+	// HTMLImgTag is a tag name of <img>
+	HTMLImgTag string = "img"
+	// HTMLTimeTag is a tag name of <time>
+	HTMLTimeTag string = "time"
+	// HTMLScriptTag is a tag name of <script>
+	HTMLScriptTag string = "script"
 )
 
 // OpenGraph represents web page information according to OGP <ogp.me>,
@@ -30,13 +41,11 @@ const (
 type OpenGraph struct {
 
 	// Policy specifies a policy to parse HTML document.
-	Policy struct {
-		TrustedTags []string
-	}
+	Policy Policy
 
 	// Basics
 	Title    string
-	Type     string
+	Type     Type
 	URL      URL
 	SiteName string
 
@@ -45,19 +54,319 @@ type OpenGraph struct {
 	Video []*OGVideo
 	Audio []*OGAudio
 
+	// Verticals
+	Article     *OGArticle
+	VideoObject *OGVideoObject
+	Product     *OGProduct
+
 	// Optionals
 	Description string
-	Determiner  string // TODO: enum?
-	Locale      string
+	Determiner  Determiner
+	Locale      Locale
 	LocaleAlt   []string
 
+	Restrictions *OGRestrictions
+
 	// Additionals
-	Favicon      string
-	CanonicalURL string
+	// DocumentTitle is the text of the first <title>, kept even if og:title exists,
+	// Policy.Strict is set or <title> is not in Policy.TrustedTags.
+	DocumentTitle string
+	Favicon       string
+	CanonicalURL  string
+	Facebook      *Facebook
+	Robots        *Robots
+	Mobile        *Mobile
+	ThemeColor    string
+	// IsAMP tells the document is an AMP page declared by <html amp> or <html ⚡>,
+	// whose CanonicalURL is supposed to be the non-AMP original.
+	IsAMP bool
+
+	// Feeds are RSS and Atom feeds declared by <link rel="alternate">.
+	Feeds []Feed
+
+	// ManifestURL is absolute URL of the web app manifest declared by <link rel="manifest">.
+	ManifestURL string
+
+	// Links are absolute URLs of <a href="...">, collected only if Policy.CollectLinks is set.
+	Links []string
+
+	// Raw holds all <meta> values keyed by property or name, only if Policy.KeepRaw is set.
+	Raw map[string][]string
+
+	// PreferredAlternate is <link rel="alternate" hreflang="..."> of Policy.PreferredLocale.
+	PreferredAlternate string
 
 	// Utils
 	HTTPClient *http.Client `json:"-"`
 	Error      error        `json:"-"`
+	Warnings   []Warning    `json:"-"`
+	// Provenance tells where a field was filled from, if not OGP, e.g. {"Title": "microdata"}.
+	Provenance map[string]string `json:"-"`
+	// Node is the root of the last parsed document, only if Policy.KeepNode is set.
+	Node *html.Node `json:"-"`
+	// Capabilities tells which kinds of metadata the document uses.
+	Capabilities Capabilities `json:"-"`
+	// Stats of the last Parse, only if Policy.CollectStats is set.
+	Stats *ParseStats `json:"-"`
+	// RedirectChain lists requested URLs in order, starting from the first one,
+	// including redirects and meta refreshes. It's kept even if Fetch fails.
+	RedirectChain []string `json:"-"`
+
+	// urls holds all og:url values in document order.
+	urls []string
+	// alternates holds <link rel="alternate" hreflang="..."> in document order.
+	alternates []*Link
+	// links is the set of Links to dedup.
+	links map[string]bool
+	// microdata holds the first value of each itemprop.
+	microdata map[string]string
+	// assigned holds scalar properties already assigned.
+	assigned map[string]bool
+	// assignedInBody holds scalar properties assigned by tags in <body>.
+	assignedInBody map[string]bool
+	// inBody tells the walker is in <body>.
+	inBody bool
+	// bodyImage is the first <img> with URL.
+	bodyImage *OGImage
+	// bodyDescription is text of the first substantive <p>.
+	bodyDescription string
+	// bodyTime is datetime of the first <time>.
+	bodyTime string
+	// applicationName is the first <meta name="application-name">.
+	applicationName string
+	// authors holds <meta name="author"> values.
+	authors []string
+	// properties counts OGP properties contributed.
+	properties int
+	// refresh is URL of <meta http-equiv="refresh">.
+	refresh string
+	// done tells the walker to stop.
+	done bool
+	// nodes counts nodes walked for Policy.MaxNodes.
+	nodes int
+	// faviconOnly tells the walker to stop at the first favicon.
+	faviconOnly bool
+	// titleOnly tells the walker to stop at the first og:title.
+	titleOnly bool
+	// xhtml tells the document is served as application/xhtml+xml.
+	xhtml bool
+	// prefixes maps custom prefixes declared by RDFa to the conventional ones, e.g. {"ogp": "og"}.
+	prefixes map[string]string
+	// dropped holds root properties whose current structure is dropped,
+	// so that its properties are dropped as well.
+	dropped map[string]bool
+	// exceeded holds root properties which exceeded its max.
+	exceeded map[string]bool
+	// ttl is og:ttl.
+	ttl time.Duration
+	// imageRoot is the root property which started the current image, either og:image or og:image:url.
+	imageRoot string
+	// videoRoot is the root property which started the current video, either og:video or og:video:url.
+	videoRoot string
+	// pendingImage holds "og:image:*" properties preceding the first og:image.
+	pendingImage *OGImage
+	// source captures the document as parsed, for FetchRaw.
+	source *bytes.Buffer
+	// rewritten tells Policy.URLRewriter is already applied by ToAbsURL.
+	rewritten bool
+}
+
+// Policy specifies a policy to parse HTML document.
+type Policy struct {
+	TrustedTags []string
+	// Strict ignores non-OGP fallbacks, such as <title>, <link> and <meta name="...">.
+	Strict bool
+	// DisableTitleFallback and DisableLinkFallback ignore <title> and <link> respectively,
+	// both of which are implied by Strict.
+	DisableTitleFallback bool
+	DisableLinkFallback  bool
+	// PreferMetaCharset lets <meta charset> override charset of Content-Type header.
+	PreferMetaCharset bool
+	// AllowedHosts and BlockedHosts restrict hosts to fetch, including redirects.
+	// An entry starting with "." matches the domain and all of its subdomains.
+	AllowedHosts []string
+	BlockedHosts []string
+	// Timeout limits the duration of Fetch, in addition to the deadline of given context.
+	Timeout time.Duration
+	// MaxBodyBytes limits size of response bodies to read, 0 means unlimited.
+	MaxBodyBytes int64
+	// MaxInlineFaviconBytes limits size of favicon to inline by InlineFavicon,
+	// 32 KiB by New and 0 means unlimited.
+	MaxInlineFaviconBytes int64
+	// MaxNodes aborts walking the document with a warning after visiting that many nodes,
+	// keeping what is parsed so far. 0 means unlimited.
+	MaxNodes int
+	// MaxImages, MaxVideos and MaxAudios cap number of structures to capture,
+	// in document order, 0 means unlimited.
+	MaxImages int
+	MaxVideos int
+	MaxAudios int
+	// MaxDescriptionRunes truncates Title and Description to that many runes including an ellipsis,
+	// cutting at the last space if any, 0 means unlimited.
+	MaxDescriptionRunes int
+	// StopAtBody stops walking the document as soon as <body> is found.
+	// OGP tags of non-conformant pages put in <body> won't be parsed.
+	StopAtBody bool
+	// IgnoreNoscript skips subtrees of <noscript>, which may hold duplicate or stale OGP tags.
+	// It matters only for trees given to ParseNode which are parsed with scripting disabled,
+	// since Parse never sees elements in <noscript>.
+	IgnoreNoscript bool
+	// PreferHead keeps scalar properties such as og:title found in <head>
+	// against the ones repeated in <body>, e.g. by a plugin of WordPress.
+	// Properties only found in <body> are still taken.
+	// Warnings of the conflicts tell where each value is declared by FirstIn and SecondIn.
+	PreferHead bool
+	// PreferredLocale such as "fr_FR" is sent as Accept-Language,
+	// and the alternate link of the locale is taken as PreferredAlternate.
+	PreferredLocale string
+	// SplitLocaleAlternates splits og:locale:alternate such as "en_US,fr_FR" into LocaleAlt entries,
+	// only if every part looks like a locale.
+	SplitLocaleAlternates bool
+	// CollectLinks lets parser collect <a href="..."> into Links.
+	CollectLinks bool
+	// GuessImageFromBody uses the first <img> as og:image if there is no og:image.
+	// The highest-resolution candidate of srcset is preferred to src.
+	// Its width and height attributes, or pixel values of its inline style, are taken as dimensions.
+	GuessImageFromBody bool
+	// GuessDescriptionFromBody uses the first <p> of at least 40 runes, outside of <nav>, <header> and <footer>,
+	// as description if there is neither og:description nor <meta name="description">.
+	// It's truncated to 300 runes.
+	GuessDescriptionFromBody bool
+	// GuessDateFromBody uses the first <time datetime="..."> as article:published_time
+	// if it's not specified. Article is created if needed.
+	GuessDateFromBody bool
+	// MicrodataFallback fills empty Title, Description and Image with
+	// schema.org microdata such as itemprop="name", unless Strict.
+	MicrodataFallback bool
+	// RecoverCommentedMeta is an experimental salvage for broken templates, which parses <meta>
+	// wrapped in HTML comments or CDATA sections as if they were not, unless Strict.
+	// Comments which fail to parse are ignored silently.
+	RecoverCommentedMeta bool
+	// AllowDataURIImages keeps og:image of data URI, which is rejected by default.
+	AllowDataURIImages bool
+	// ImageRenditions keeps og:image:width and og:image:height repeated without a new og:image
+	// as Renditions of the image, instead of overriding dimensions with a warning. It's nonstandard.
+	ImageRenditions bool
+	// AllowedImageHosts restricts hosts of og:image, og:image:secure_url and guessed images,
+	// matched like AllowedHosts. Images of other hosts, including data URIs, are dropped with a warning.
+	// Empty means all hosts are allowed.
+	AllowedImageHosts []string
+	// AllowedURLSchemes are schemes allowed in URL fields such as og:image, og:url and favicon,
+	// "http" and "https" by default. URLs of other schemes such as "javascript:" are skipped with a warning.
+	// Relative URLs are always allowed.
+	AllowedURLSchemes []string
+	// FollowMetaRefresh lets Fetch follow <meta http-equiv="refresh"> of a page without OGP.
+	FollowMetaRefresh bool
+	// Header is added to requests of Fetch, e.g. to override Accept,
+	// which is "text/html,application/xhtml+xml" by default.
+	Header http.Header
+	// RequestFactory constructs requests of Fetch instead of http.NewRequest, e.g. to sign them.
+	// On redirects, http.Client forwards its headers except sensitive ones to other domains.
+	RequestFactory func(ctx context.Context, url string) (*http.Request, error) `json:"-"`
+	// FirstWins keeps the first value of repeated scalar properties such as og:title,
+	// instead of the last one. Structures such as og:image accumulate regardless.
+	FirstWins bool
+	// ResolveConflict decides the value of a scalar property such as og:title set more than once,
+	// given the existing and the incoming value, instead of FirstWins, PreferHead and URLPolicy.
+	ResolveConflict func(property, existing, incoming string) string `json:"-"`
+	// IgnoreEmptyValues skips <meta> with empty or blank content, true by New.
+	// Skipped values are not regarded as assigned, so they neither clobber earlier values
+	// nor block later ones with FirstWins, and never create empty structures such as og:image.
+	IgnoreEmptyValues bool
+	// OnMeta is called for each <meta> in document order, after it's contributed to OpenGraph.
+	// Returning false stops parsing the rest of the document.
+	OnMeta func(Meta) bool `json:"-"`
+	// OnHeadComplete is called once <head> is walked, and returning false stops walking <body>.
+	OnHeadComplete func() bool `json:"-"`
+	// KeepRaw keeps all <meta> values in Raw, keyed by property or name.
+	KeepRaw bool
+	// CapturePrefixes such as "product:" limits Raw to properties or names with these prefixes.
+	// Setting it enables capturing even without KeepRaw.
+	CapturePrefixes []string
+	// ImageFetcher fetches images for ResolveImageDimensions and ValidateImages,
+	// HTTPImageFetcher with HTTPClient by default.
+	ImageFetcher ImageFetcher `json:"-"`
+	// ResolveSVGDimensions lets ResolveImageDimensions read width and height, or viewBox,
+	// of the root <svg> element of SVG images instead of leaving them zero.
+	ResolveSVGDimensions bool
+	// MaxIdleConns shapes the transport used when HTTPClient is nil or http.DefaultClient,
+	// both in total and per host, to reuse connections across fetches.
+	MaxIdleConns int
+	// ForceAttemptHTTP2 tells if the transport used when HTTPClient is nil or http.DefaultClient attempts HTTP/2.
+	// nil means true as http.DefaultTransport does, so set it to false only to opt out.
+	// A client supplied by the caller is never modified.
+	ForceAttemptHTTP2 *bool
+	// PreferIPv4 and PreferIPv6 restrict the transport used when HTTPClient is nil or http.DefaultClient
+	// to dial over IPv4 or IPv6 only, e.g. where routing of the other is broken. PreferIPv4 wins if both are set.
+	PreferIPv4 bool
+	PreferIPv6 bool
+	// Cache lets Fetch reuse OpenGraph fetched before by URL, and store the fetched one
+	// for og:ttl if specified.
+	Cache Cache `json:"-"`
+	// DateLayouts are time layouts tried in order after the built-in ISO 8601 layouts,
+	// to parse date properties such as article:published_time.
+	DateLayouts []string
+	// StripTrackingParams lets ToAbsURL remove tracking query parameters such as utm_source
+	// from og:url and CanonicalURL.
+	StripTrackingParams bool
+	// TrackingParams overrides the default parameters to strip, e.g. "utm_*" and "fbclid".
+	// A name ending with "*" matches as a prefix.
+	TrackingParams []string
+	// UpgradeInsecureURLs lets ToAbsURL rewrite http:// favicon and og:image without secure_url
+	// to https:// on https pages, with a warning. It's best-effort and may break sites without HTTPS.
+	UpgradeInsecureURLs bool
+	// URLRewriter rewrites URLs of assets resolved by ToAbsURL, e.g. to go through an image proxy,
+	// which are URL and SURL of Image, Video and Audio, and Favicon.
+	// og:url and CanonicalURL are not rewritten since they identify the page rather than assets.
+	URLRewriter func(string) string `json:"-"`
+	// KeepNode keeps the parsed document in Node for further extraction by callers.
+	// Note that it holds the whole tree in memory as long as OpenGraph is referenced.
+	KeepNode bool
+	// CollectStats lets Parse fill Stats.
+	CollectStats bool
+	// CollectWarnings lets parser record advisories into Warnings.
+	CollectWarnings bool
+	// URLPolicy decides og.URL.Value when there are more than one og:url.
+	URLPolicy URLPolicy
+}
+
+// DefaultPolicy returns Policy which New sets.
+func DefaultPolicy() Policy {
+	return Policy{
+		TrustedTags:       []string{HTMLMetaTag, HTMLLinkTag, HTMLTitleTag},
+		IgnoreEmptyValues: true,
+
+		MaxInlineFaviconBytes: defaultMaxInlineFaviconBytes,
+	}
+}
+
+// clone returns a copy of p which doesn't share slices with p.
+// Funcs and interfaces such as Cache are shared as they are.
+func (p Policy) clone() Policy {
+	c := p
+	c.TrustedTags = copyStrings(p.TrustedTags)
+	c.AllowedHosts = copyStrings(p.AllowedHosts)
+	c.BlockedHosts = copyStrings(p.BlockedHosts)
+	c.CapturePrefixes = copyStrings(p.CapturePrefixes)
+	c.DateLayouts = copyStrings(p.DateLayouts)
+	c.TrackingParams = copyStrings(p.TrackingParams)
+	c.AllowedURLSchemes = copyStrings(p.AllowedURLSchemes)
+	c.AllowedImageHosts = copyStrings(p.AllowedImageHosts)
+	if p.Header != nil {
+		c.Header = p.Header.Clone()
+	}
+	if p.ForceAttemptHTTP2 != nil {
+		force := *p.ForceAttemptHTTP2
+		c.ForceAttemptHTTP2 = &force
+	}
+	return c
+}
+
+func copyStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	return append([]string{}, s...)
 }
 
 // URL includes *url.URL
@@ -71,7 +380,7 @@ type URL struct {
 // New creates new OpenGraph struct with specified URL.
 func New(rawurl string) *OpenGraph {
 	og := new(OpenGraph)
-	og.Policy.TrustedTags = []string{HTMLMetaTag, HTMLLinkTag, HTMLTitleTag}
+	og.Policy = DefaultPolicy()
 	og.HTTPClient = http.DefaultClient
 	og.Image = []*OGImage{}
 	og.Video = []*OGVideo{}
@@ -105,46 +414,301 @@ func FetchWithContext(ctx context.Context, rawurl string, customHTTPClient ...*h
 		og.HTTPClient = customHTTPClient[0]
 	}
 
-	req, err := http.NewRequest("GET", og.URL.String(), nil)
-	if err != nil {
+	return og, og.Fetch(ctx)
+}
+
+// FetchWithTimeout creates and parses OpenGraph with specified URL,
+// giving up after given timeout.
+func FetchWithTimeout(timeout time.Duration, rawurl string, customHTTPClient ...*http.Client) (*OpenGraph, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return FetchWithContext(ctx, rawurl, customHTTPClient...)
+}
+
+// FetchJSON creates and parses OpenGraph with specified URL,
+// and returns it marshaled to JSON with absolute URLs, e.g. for command line tools.
+// Use OpenGraph.FetchJSON to configure Policy beforehand.
+func FetchJSON(ctx context.Context, rawurl string, customHTTPClient ...*http.Client) ([]byte, error) {
+	og := New(rawurl)
+	if len(customHTTPClient) != 0 {
+		og.HTTPClient = customHTTPClient[0]
+	}
+	return og.FetchJSON(ctx)
+}
+
+// FetchJSON fetches og.URL like Fetch, and returns og marshaled to JSON with absolute URLs.
+func (og *OpenGraph) FetchJSON(ctx context.Context) ([]byte, error) {
+	if err := og.Fetch(ctx); err != nil {
 		return nil, err
 	}
+	return json.Marshal(og.ToAbsURL())
+}
+
+// FetchRaw creates and parses OpenGraph with specified URL like FetchWithContext,
+// and also returns the document as the parser saw. Use OpenGraph.FetchRaw to configure Policy beforehand,
+// e.g. Policy.MaxBodyBytes.
+func FetchRaw(ctx context.Context, rawurl string, customHTTPClient ...*http.Client) (*OpenGraph, []byte, error) {
+	og := New(rawurl)
+	if len(customHTTPClient) != 0 {
+		og.HTTPClient = customHTTPClient[0]
+	}
+	raw, err := og.FetchRaw(ctx)
+	return og, raw, err
+}
+
+// FetchRaw fetches og.URL like Fetch, and also returns the document as the parser saw,
+// i.e. limited by Policy.MaxBodyBytes and decoded to UTF-8. If meta refreshes are followed,
+// it's the last document. The document read so far is returned even on error.
+func (og *OpenGraph) FetchRaw(ctx context.Context) ([]byte, error) {
+	if og.Error != nil {
+		return nil, og.Error
+	}
+	og.source = new(bytes.Buffer)
+	defer func() { og.source = nil }()
+	err := og.Fetch(ctx)
+	return og.source.Bytes(), err
+}
+
+// Fetch fetches og.URL and parses the document with og.Policy.
+// Use this instead of FetchWithContext to configure og before fetching.
+// If the response body is broken halfway, e.g. exceeding Policy.MaxBodyBytes,
+// og keeps informations parsed from the part received and the error is returned.
+// URL schemes are governed by the transport of og.HTTPClient, so that file:// or custom schemes
+// can be fetched by registering http.RoundTripper with http.Transport.RegisterProtocol.
+func (og *OpenGraph) Fetch(ctx context.Context) error {
+	if og.Error != nil {
+		return og.Error
+	}
+
+	source := og.URL.Source
+	if og.Policy.Cache != nil && og.fromCache() {
+		return nil
+	}
+
+	if og.Policy.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, og.Policy.Timeout)
+		defer cancel()
+	}
+
+	for i := 0; ; i++ {
+		if err := og.fetch(ctx); err != nil {
+			return err
+		}
+		target := og.refreshTarget()
+		if target == "" {
+			if og.Policy.Cache != nil {
+				og.toCache(source)
+			}
+			return nil
+		}
+		if i >= maxMetaRefresh {
+			return fmt.Errorf("stopped after %d meta refreshes", maxMetaRefresh)
+		}
+		og.refetch(target)
+	}
+}
+
+// fetch fetches og.URL once and parses the document.
+func (og *OpenGraph) fetch(ctx context.Context) error {
+	if og.Error != nil {
+		return og.Error
+	}
+
+	if err := og.checkHost(og.URL.URL); err != nil {
+		return err
+	}
+
+	req, err := og.newRequest(ctx)
+	if err != nil {
+		return err
+	}
 
 	req = req.WithContext(ctx)
 
-	res, err := og.HTTPClient.Do(req)
+	for key, values := range og.Policy.Header {
+		req.Header[http.CanonicalHeaderKey(key)] = values
+	}
+	if req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", defaultAccept)
+	}
+
+	if og.Policy.PreferredLocale != "" && req.Header.Get("Accept-Language") == "" {
+		req.Header.Set("Accept-Language", Locale(og.Policy.PreferredLocale).acceptLanguage())
+	}
+
+	og.RedirectChain = append(og.RedirectChain, req.URL.String())
+	res, err := og.recordRedirects(og.client()).Do(req)
 	if err != nil {
-		return og, err
+		return err
 	}
 	defer res.Body.Close()
 
-	if !strings.HasPrefix(res.Header.Get("Content-Type"), "text/html") {
-		return og, fmt.Errorf("Content type must be text/html")
+	if err := og.checkContentLength(res); err != nil {
+		return err
 	}
 
-	if err = og.Parse(res.Body); err != nil {
-		return og, err
+	contentType := res.Header.Get("Content-Type")
+	og.xhtml = strings.HasPrefix(contentType, "application/xhtml+xml")
+	if !strings.HasPrefix(contentType, "text/html") && !og.xhtml {
+		return fmt.Errorf("Content type must be text/html or application/xhtml+xml")
 	}
 
-	return og, err
+	body, err := decompress(res)
+	if err != nil {
+		return err
+	}
+
+	if og.source != nil {
+		og.source.Reset()
+	}
+
+	return og.Parse(og.decode(og.limit(body), contentType))
+}
+
+// newRequest constructs the request to fetch og.URL, with Policy.RequestFactory if given.
+func (og *OpenGraph) newRequest(ctx context.Context) (*http.Request, error) {
+	if og.Policy.RequestFactory != nil {
+		return og.Policy.RequestFactory(ctx, og.URL.String())
+	}
+	return http.NewRequest("GET", og.URL.String(), nil)
 }
 
 // Parse parses http.Response.Body and construct OpenGraph informations.
 // Caller should close body after it get parsed.
+// Contents of <noscript> are never parsed, because the document is parsed
+// with scripting enabled and they are treated as raw text. See Policy.IgnoreNoscript for ParseNode.
+// A leading BOM is stripped, and UTF-16 documents with BOM are transcoded to UTF-8.
+// XHTML documents, served as application/xhtml+xml or starting with XML prolog,
+// may self-close any elements such as <script src="..."/>.
+// If reading body fails halfway, the part read so far is parsed and the error is returned,
+// so og may be partially populated on error.
 func (og *OpenGraph) Parse(body io.Reader) error {
 	if og.Error != nil {
 		return og.Error
 	}
+	start := time.Now()
+	partial := &partialReader{r: body}
+	body = stripBOM(partial)
+	if og.source != nil {
+		body = io.TeeReader(body, og.source)
+	}
+	body, xhtml := og.isXHTML(body)
+	if xhtml {
+		var err error
+		if body, err = expandSelfClosing(body); err != nil {
+			return err
+		}
+	}
 	node, err := html.Parse(body)
 	if err != nil {
 		return err
 	}
-	og.walk(node)
+	og.parseNode(node, start)
+	return partial.err
+}
+
+// partialReader reports io.EOF instead of the first error of r, and keeps the error,
+// so that the document read so far can be parsed.
+type partialReader struct {
+	r   io.Reader
+	err error
+}
+
+func (pr *partialReader) Read(p []byte) (int, error) {
+	if pr.err != nil {
+		return 0, io.EOF
+	}
+	n, err := pr.r.Read(p)
+	if err != nil && err != io.EOF {
+		pr.err = err
+		err = io.EOF
+	}
+	return n, err
+}
+
+// ParseReaderWithCharset parses body like Parse, transcoding it to UTF-8 as Fetch does,
+// e.g. for documents read from disk. declaredCharset such as "Shift_JIS" plays the role of
+// charset of Content-Type header, and if it's empty, the charset is detected by BOM and <meta>,
+// and UTF-8 is assumed if neither declares it.
+func (og *OpenGraph) ParseReaderWithCharset(body io.Reader, declaredCharset string) error {
+	contentType := "text/html"
+	if declaredCharset != "" {
+		contentType += "; charset=" + declaredCharset
+	}
+	return og.Parse(og.decode(body, contentType))
+}
+
+// ParseNode constructs OpenGraph informations from already parsed HTML node.
+// The node doesn't have to be a document, e.g. <head> element, and only its subtree is walked.
+func (og *OpenGraph) ParseNode(n *html.Node) error {
+	if og.Error != nil {
+		return og.Error
+	}
+	og.parseNode(n, time.Now())
 	return nil
 }
 
+func (og *OpenGraph) parseNode(n *html.Node, start time.Time) {
+	if og.Policy.CollectStats {
+		og.Stats = &ParseStats{}
+	}
+	if og.Policy.KeepNode {
+		og.Node = n
+	}
+	og.done = false
+	og.nodes = 0
+	og.inBody = false
+	og.walk(n)
+	og.complete()
+	if og.Policy.CollectStats {
+		og.Stats.Images = len(og.Image)
+		og.Stats.Duration = time.Since(start)
+	}
+}
+
+// ParseWithContext parses body like Parse, but aborts with ctx.Err() when ctx is done.
+// Since html.Parse can't be canceled, reads from body fail after cancellation instead,
+// so a read already blocking on body is not interrupted.
+func (og *OpenGraph) ParseWithContext(ctx context.Context, body io.Reader) error {
+	if err := og.Parse(&contextReader{ctx: ctx, r: body}); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+// contextReader fails reading once its context is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (cr *contextReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cr.r.Read(p)
+}
+
+// complete decides OpenGraph informations after all tags are contributed.
+func (og *OpenGraph) complete() {
+	if !strings.HasPrefix(string(og.Type), "video.") {
+		og.VideoObject = nil
+	}
+	og.applyMicrodata()
+	og.guessImageFromBody()
+	og.guessDateFromBody()
+	og.guessDescriptionFromBody()
+	og.fallbackSiteName()
+	og.truncateTexts()
+	og.chooseURL()
+	og.choosePreferredAlternate()
+	og.Capabilities.HasVerticalType = og.hasVerticalType()
+	og.warnIncompleteImages()
+}
+
 func (og *OpenGraph) satisfied() bool {
-	return false
+	return og.done
 }
 
 func (og *OpenGraph) walk(n *html.Node) error {
@@ -153,17 +717,98 @@ func (og *OpenGraph) walk(n *html.Node) error {
 		return nil
 	}
 
+	if og.Policy.MaxNodes > 0 && og.nodes >= og.Policy.MaxNodes {
+		og.warn("html", "aborted after %d nodes", og.nodes)
+		og.done = true
+		return nil
+	}
+	og.nodes++
+
+	if og.Policy.CollectStats {
+		og.Stats.Nodes++
+	}
+
+	if n.Type == html.CommentNode && og.Policy.RecoverCommentedMeta && !og.Policy.Strict {
+		og.recoverCommentedMeta(n)
+		return nil
+	}
+
 	if n.Type == html.ElementNode {
-		if !og.trust(n.Data) {
+		// Foreign content such as <svg><title> is never HTML metadata.
+		if n.Namespace == "svg" || n.Namespace == "math" {
+			return nil
+		}
+		if n.DataAtom == atom.Noscript && og.Policy.IgnoreNoscript {
 			return nil
 		}
+		if n.Data == "html" && isAMP(n) {
+			og.IsAMP = true
+		}
+		if n.Data == "html" || n.Data == "head" {
+			og.declarePrefixes(n)
+		}
+		if n.Data == "body" {
+			if og.Policy.StopAtBody {
+				og.done = true
+				return nil
+			}
+			og.inBody = true
+		}
+		if og.Policy.MicrodataFallback && !og.Policy.Strict {
+			og.collectMicrodata(n)
+		}
+		// DocumentTitle is kept regardless of TrustedTags as well as Strict.
+		if n.Data == HTMLTitleTag && og.DocumentTitle == "" {
+			og.DocumentTitle = TitleTag(n).Text
+		}
+		switch n.Data {
+		case HTMLTitleTag, HTMLMetaTag, HTMLLinkTag:
+			if !og.trust(n.Data) {
+				return nil
+			}
+		}
 		switch n.Data {
 		case HTMLTitleTag:
+			if og.Policy.Strict || og.Policy.DisableTitleFallback {
+				return nil
+			}
 			return TitleTag(n).Contribute(og)
 		case HTMLMetaTag:
-			return MetaTag(n).Contribute(og)
+			m := MetaTag(n)
+			m.Property = og.resolvePrefix(m.Property)
+			err := m.Contribute(og)
+			if og.Policy.CollectStats {
+				og.Stats.Metas++
+			}
+			if og.Policy.OnMeta != nil && !og.Policy.OnMeta(*m) {
+				og.done = true
+			}
+			return err
 		case HTMLLinkTag:
+			if og.Policy.Strict || og.Policy.DisableLinkFallback {
+				return nil
+			}
 			return LinkTag(n).Contribute(og)
+		case HTMLAnchorTag:
+			if og.Policy.CollectLinks {
+				AnchorTag(n).Contribute(og)
+			}
+		case HTMLImgTag:
+			if og.Policy.GuessImageFromBody && !og.Policy.Strict {
+				ImgTag(n).Contribute(og)
+			}
+		case HTMLParagraphTag:
+			if og.Policy.GuessDescriptionFromBody && !og.Policy.Strict && !inNavigation(n) {
+				ParagraphTag(n).Contribute(og)
+			}
+		case HTMLTimeTag:
+			if og.Policy.GuessDateFromBody && !og.Policy.Strict {
+				TimeTag(n).Contribute(og)
+			}
+		case HTMLScriptTag:
+			if isJSONLD(n) {
+				og.Capabilities.HasJSONLD = true
+			}
 		}
 	}
 
@@ -171,6 +816,12 @@ func (og *OpenGraph) walk(n *html.Node) error {
 		og.walk(child)
 	}
 
+	if n.Type == html.ElementNode && n.Data == "head" && og.Policy.OnHeadComplete != nil && !og.done {
+		if !og.Policy.OnHeadComplete() {
+			og.done = true
+		}
+	}
+
 	return nil
 }
 
@@ -178,22 +829,263 @@ func (og *OpenGraph) trust(tagName string) bool {
 	if len(og.Policy.TrustedTags) == 0 {
 		return true
 	}
-	return sort.SearchStrings(og.Policy.TrustedTags, tagName) != len(og.Policy.TrustedTags)
+	for _, trusted := range og.Policy.TrustedTags {
+		if trusted == tagName {
+			return true
+		}
+	}
+	return false
+}
+
+// assign sets value of a scalar property to field, unless it's already assigned and Policy.FirstWins,
+// or it's assigned in <head> and Policy.PreferHead. Policy.ResolveConflict overrides them if given.
+func (og *OpenGraph) assign(property string, field *string, value string) {
+	// Conflicting og:url is warned by chooseURL instead.
+	if og.assigned[property] && *field != value && property != "og:url" {
+		og.warnConflict(property, *field, value, og.assignedInBody[property], og.inBody)
+	}
+	if og.Policy.ResolveConflict != nil && og.assigned[property] {
+		*field = og.Policy.ResolveConflict(property, *field, value)
+		return
+	}
+	if og.Policy.FirstWins && og.assigned[property] {
+		return
+	}
+	if og.Policy.PreferHead && og.inBody && og.assigned[property] && !og.assignedInBody[property] {
+		return
+	}
+	if og.assigned == nil {
+		og.assigned = map[string]bool{}
+	}
+	og.assigned[property] = true
+	if og.inBody {
+		if og.assignedInBody == nil {
+			og.assignedInBody = map[string]bool{}
+		}
+		og.assignedInBody[property] = true
+	} else {
+		delete(og.assignedInBody, property)
+	}
+	*field = value
+}
+
+// provide records the source of a field filled by non-OGP fallback.
+func (og *OpenGraph) provide(field, source string) {
+	if og.Provenance == nil {
+		og.Provenance = map[string]string{}
+	}
+	og.Provenance[field] = source
+}
+
+// exceeds reports if a new structure of given root property should be dropped
+// because count already reached max, and warns only at the first time.
+func (og *OpenGraph) exceeds(property string, count, max int) bool {
+	if max <= 0 || count < max {
+		return false
+	}
+	if !og.exceeded[property] {
+		if og.exceeded == nil {
+			og.exceeded = map[string]bool{}
+		}
+		og.exceeded[property] = true
+		og.warn(property, "exceeds max %d, the rest are dropped", max)
+	}
+	return true
 }
 
-// ToAbsURL make og.Image and og.Favicon absolute URL if relative.
+// rejectsDataURI reports if given image URL is a data URI not allowed by Policy.AllowDataURIImages.
+func (og *OpenGraph) rejectsDataURI(property, rawurl string) bool {
+	if og.Policy.AllowDataURIImages || !strings.HasPrefix(strings.ToLower(strings.TrimSpace(rawurl)), "data:") {
+		return false
+	}
+	og.warn(property, "data URI is rejected")
+	return true
+}
+
+// defaultAccept is Accept header of Fetch unless specified by Policy.Header or Policy.RequestFactory.
+const defaultAccept = "text/html,application/xhtml+xml"
+
+// defaultURLSchemes are schemes of URL fields allowed by default.
+var defaultURLSchemes = []string{"http", "https"}
+
+// rejectsScheme reports if given URL has a scheme not allowed by Policy.AllowedURLSchemes,
+// such as "javascript:", with a warning. Relative URLs are always allowed,
+// and so are data URIs of og:image if Policy.AllowDataURIImages is set.
+func (og *OpenGraph) rejectsScheme(property, rawurl string) bool {
+	scheme := urlScheme(rawurl)
+	if scheme == "" || scheme == "data" && og.Policy.AllowDataURIImages && strings.HasPrefix(property, "og:image") {
+		return false
+	}
+	allowed := og.Policy.AllowedURLSchemes
+	if len(allowed) == 0 {
+		allowed = defaultURLSchemes
+	}
+	for _, a := range allowed {
+		if strings.EqualFold(a, scheme) {
+			return false
+		}
+	}
+	og.warn(property, "URL of scheme %q is rejected", scheme)
+	return true
+}
+
+// rejectsImageHost reports if given image URL, resolved against og.URL, has a host
+// not allowed by Policy.AllowedImageHosts, with a warning.
+func (og *OpenGraph) rejectsImageHost(property, rawurl string) bool {
+	if len(og.Policy.AllowedImageHosts) == 0 {
+		return false
+	}
+	u, err := url.Parse(og.abs(trimURL(rawurl)))
+	if err == nil && matchHost(strings.ToLower(u.Hostname()), og.Policy.AllowedImageHosts) {
+		return false
+	}
+	og.warn(property, "host of %q is not allowed", rawurl)
+	return true
+}
+
+// rejectsImageURL reports if given image URL is rejected by any of rejectsDataURI,
+// rejectsScheme and rejectsImageHost, as og:image is.
+func (og *OpenGraph) rejectsImageURL(property, rawurl string) bool {
+	return og.rejectsDataURI(property, rawurl) || og.rejectsScheme(property, rawurl) || og.rejectsImageHost(property, rawurl)
+}
+
+// urlScheme returns lowercased scheme of rawurl, or empty if it's relative.
+func urlScheme(rawurl string) string {
+	v := trimURL(rawurl)
+	i := strings.IndexAny(v, ":/?#")
+	if i <= 0 || v[i] != ':' {
+		return ""
+	}
+	return strings.ToLower(v[:i])
+}
+
+// drop marks the current structure of given root property as dropped.
+func (og *OpenGraph) drop(property string) {
+	if og.dropped == nil {
+		og.dropped = map[string]bool{}
+	}
+	og.dropped[property] = true
+}
+
+// accept marks the current structure of given root property as accepted.
+func (og *OpenGraph) accept(property string) {
+	delete(og.dropped, property)
+}
+
+// ToAbsURL make URL and SURL of og.Image, og.Video and og.Audio, og.Favicon, og.CanonicalURL and og:url
+// absolute URL if relative. A relative og:url is invalid by OGP, so it's warned when resolved.
+// If Policy.StripTrackingParams is set, tracking parameters are removed from og:url and og.CanonicalURL.
+// If Policy.UpgradeInsecureURLs is set, http favicon and images without secure_url are upgraded to https.
+// Policy.URLRewriter is applied to the resolved URLs of assets at last, only by the first call of ToAbsURL.
 func (og *OpenGraph) ToAbsURL() *OpenGraph {
 	for _, img := range og.Image {
 		img.URL = og.abs(img.URL)
+		img.SURL = og.absIfAny(img.SURL)
+	}
+	for _, video := range og.Video {
+		video.URL = og.abs(video.URL)
+		video.SURL = og.absIfAny(video.SURL)
+	}
+	for _, audio := range og.Audio {
+		audio.URL = og.abs(audio.URL)
+		audio.SURL = og.absIfAny(audio.SURL)
 	}
 	og.Favicon = og.abs(og.Favicon)
+	if og.CanonicalURL != "" {
+		og.CanonicalURL = og.abs(og.CanonicalURL)
+	}
+	if og.URL.Value != "" {
+		if v := og.abs(og.URL.Value); v != og.URL.Value {
+			og.warn("og:url", "relative URL %q is resolved to %q", og.URL.Value, v)
+			og.URL.Value = v
+		}
+	}
+	if og.Policy.StripTrackingParams {
+		og.URL.Value = og.stripTrackingParams(og.URL.Value)
+		og.CanonicalURL = og.stripTrackingParams(og.CanonicalURL)
+	}
+	if og.Policy.UpgradeInsecureURLs {
+		og.upgradeInsecureURLs()
+	}
+	if og.Policy.URLRewriter != nil && !og.rewritten {
+		og.rewriteURLs()
+		og.rewritten = true
+	}
 	return og
 }
 
+// absIfAny returns og.abs(raw), or empty if raw is empty.
+func (og *OpenGraph) absIfAny(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	return og.abs(raw)
+}
+
+// upgradeInsecureURLs rewrites http:// favicon and og:image without secure_url to https://,
+// if the page is served over https.
+func (og *OpenGraph) upgradeInsecureURLs() {
+	if og.URL.URL == nil || og.URL.Scheme != "https" {
+		return
+	}
+	upgrade := func(property, u string) string {
+		if !strings.HasPrefix(u, "http://") {
+			return u
+		}
+		upgraded := "https://" + strings.TrimPrefix(u, "http://")
+		og.warn(property, "insecure URL %q is upgraded to %q", u, upgraded)
+		return upgraded
+	}
+	og.Favicon = upgrade("favicon", og.Favicon)
+	for _, img := range og.Image {
+		if img.SURL == "" {
+			img.URL = upgrade("og:image", img.URL)
+		}
+	}
+}
+
+// rewriteURLs applies Policy.URLRewriter to non-empty URLs of assets resolved by ToAbsURL.
+func (og *OpenGraph) rewriteURLs() {
+	rewrite := func(u string) string {
+		if u == "" {
+			return u
+		}
+		return og.Policy.URLRewriter(u)
+	}
+	for _, img := range og.Image {
+		img.URL, img.SURL = rewrite(img.URL), rewrite(img.SURL)
+	}
+	for _, video := range og.Video {
+		video.URL, video.SURL = rewrite(video.URL), rewrite(video.SURL)
+	}
+	for _, audio := range og.Audio {
+		audio.URL, audio.SURL = rewrite(audio.URL), rewrite(audio.SURL)
+	}
+	og.Favicon = rewrite(og.Favicon)
+}
+
+// AbsoluteImageURLs returns absolute URLs of og.Image without modifying og.Image.
+func (og *OpenGraph) AbsoluteImageURLs() []string {
+	urls := make([]string, 0, len(og.Image))
+	for _, img := range og.Image {
+		urls = append(urls, og.abs(img.URL))
+	}
+	return urls
+}
+
+// AbsoluteVideoURLs returns absolute URLs of og.Video without modifying og.Video.
+func (og *OpenGraph) AbsoluteVideoURLs() []string {
+	urls := make([]string, 0, len(og.Video))
+	for _, video := range og.Video {
+		urls = append(urls, og.abs(video.URL))
+	}
+	return urls
+}
+
 // abs make given URL absolute.
 func (og *OpenGraph) abs(raw string) string {
-	u, _ := url.Parse(raw)
-	if u.IsAbs() {
+	u, err := url.Parse(raw)
+	if err != nil || u.IsAbs() {
 		return raw
 	}
 	if u.Scheme == "" {
@@ -0,0 +1,65 @@
+package opengraph
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// ogpNamespaces maps namespaces of ogp.me, without scheme and trailing "#" or "/",
+// to their conventional prefixes.
+var ogpNamespaces = map[string]string{
+	"ogp.me/ns":         "og",
+	"ogp.me/ns/fb":      "fb",
+	"ogp.me/ns/article": "article",
+	"ogp.me/ns/book":    "book",
+	"ogp.me/ns/profile": "profile",
+	"ogp.me/ns/website": "website",
+	"ogp.me/ns/music":   "music",
+	"ogp.me/ns/video":   "video",
+	"ogp.me/ns/product": "product",
+}
+
+// declarePrefixes reads RDFa prefix="og: http://ogp.me/ns#" and xmlns:og="http://ogp.me/ns#"
+// of given <html> or <head>, and records custom prefixes bound to namespaces of ogp.me.
+func (og *OpenGraph) declarePrefixes(n *html.Node) {
+	for _, attr := range n.Attr {
+		switch {
+		case attr.Key == "prefix":
+			fields := strings.Fields(attr.Val)
+			for i := 0; i+1 < len(fields); i += 2 {
+				if strings.HasSuffix(fields[i], ":") {
+					og.declarePrefix(strings.TrimSuffix(fields[i], ":"), fields[i+1])
+				}
+			}
+		case strings.HasPrefix(attr.Key, "xmlns:"):
+			og.declarePrefix(strings.TrimPrefix(attr.Key, "xmlns:"), attr.Val)
+		}
+	}
+}
+
+func (og *OpenGraph) declarePrefix(prefix, namespace string) {
+	namespace = strings.TrimSpace(namespace)
+	namespace = strings.TrimPrefix(strings.TrimPrefix(namespace, "http://"), "https://")
+	conventional, ok := ogpNamespaces[strings.TrimRight(namespace, "#/")]
+	if !ok || prefix == "" || prefix == conventional {
+		return
+	}
+	if og.prefixes == nil {
+		og.prefixes = map[string]string{}
+	}
+	og.prefixes[strings.ToLower(prefix)] = conventional
+}
+
+// resolvePrefix rewrites given property of a custom prefix to the conventional one,
+// e.g. "ogp:title" to "og:title" if "ogp" is bound to http://ogp.me/ns#.
+func (og *OpenGraph) resolvePrefix(property string) string {
+	i := strings.Index(property, ":")
+	if i <= 0 || len(og.prefixes) == 0 {
+		return property
+	}
+	if conventional, ok := og.prefixes[strings.ToLower(property[:i])]; ok {
+		return conventional + property[i:]
+	}
+	return property
+}
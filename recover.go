@@ -0,0 +1,39 @@
+package opengraph
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// recoverCommentedMeta walks <meta> found in given comment node, for Policy.RecoverCommentedMeta.
+// A CDATA section in HTML is parsed as a bogus comment ending at the first ">",
+// e.g. "[CDATA[<meta ..." of "<![CDATA[<meta ...>]]>", so the cut tag is restored.
+// Tags following it are not in the comment, and parsed as usual.
+func (og *OpenGraph) recoverCommentedMeta(comment *html.Node) {
+	data := comment.Data
+	if !strings.Contains(strings.ToLower(data), "<meta") {
+		return
+	}
+	if strings.HasPrefix(data, "[CDATA[") {
+		data = strings.TrimPrefix(data, "[CDATA[") + ">"
+	}
+	nodes, err := html.ParseFragment(strings.NewReader(data), &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body})
+	if err != nil {
+		return
+	}
+	for _, n := range nodes {
+		og.walkCommentedMeta(n)
+	}
+}
+
+func (og *OpenGraph) walkCommentedMeta(n *html.Node) {
+	if n.Type == html.ElementNode && n.Data == HTMLMetaTag {
+		og.walk(n)
+		return
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		og.walkCommentedMeta(child)
+	}
+}
@@ -0,0 +1,31 @@
+package opengraph
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// maxRedirects is the same limit as the default policy of http.Client.
+const maxRedirects = 10
+
+// recordRedirects returns a copy of given client which appends every redirect target to og.RedirectChain.
+func (og *OpenGraph) recordRedirects(client *http.Client) *http.Client {
+	c := *client
+	next := c.CheckRedirect
+	c.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		og.RedirectChain = append(og.RedirectChain, req.URL.String())
+		return checkRedirect(next, req, via)
+	}
+	return &c
+}
+
+// checkRedirect calls next if given, otherwise applies the default policy of http.Client.
+func checkRedirect(next func(*http.Request, []*http.Request) error, req *http.Request, via []*http.Request) error {
+	if next != nil {
+		return next(req, via)
+	}
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package opengraph
+
+import "strings"
+
+// maxMetaRefresh is the max number of <meta http-equiv="refresh"> to follow,
+// same as the default of http.Client for redirects.
+const maxMetaRefresh = 10
+
+// refreshURL extracts URL from content of <meta http-equiv="refresh">, e.g. "0;url=https://example.com/".
+func refreshURL(content string) string {
+	i := strings.IndexAny(content, ";,")
+	if i < 0 {
+		return ""
+	}
+	rest := strings.TrimSpace(content[i+1:])
+	if len(rest) < 4 || !strings.EqualFold(rest[:3], "url") {
+		return ""
+	}
+	rest = strings.TrimSpace(rest[3:])
+	if !strings.HasPrefix(rest, "=") {
+		return ""
+	}
+	return strings.Trim(strings.TrimSpace(rest[1:]), `'"`)
+}
+
+// refreshTarget returns URL to follow if the document is a refresh stub without OGP.
+func (og *OpenGraph) refreshTarget() string {
+	if !og.Policy.FollowMetaRefresh || og.refresh == "" || og.properties != 0 {
+		return ""
+	}
+	return og.abs(og.refresh)
+}
+
+// refetch resets og to fetch given URL with the same Policy and HTTPClient.
+func (og *OpenGraph) refetch(rawurl string) {
+	next := New(rawurl)
+	next.Policy = og.Policy
+	next.HTTPClient = og.HTTPClient
+	next.Warnings = og.Warnings
+	next.RedirectChain = og.RedirectChain
+	next.source = og.source
+	*og = *next
+}
@@ -0,0 +1,43 @@
+package opengraph
+
+import "strings"
+
+// Robots represents directives of <meta name="robots"> and <meta name="googlebot">.
+type Robots struct {
+	// Directives are lowercased directives in document order, e.g. ["noindex", "nofollow"].
+	Directives []string
+}
+
+// NoIndex returns if "noindex" or "none" is specified.
+func (r *Robots) NoIndex() bool {
+	return r.Has("noindex") || r.Has("none")
+}
+
+// NoFollow returns if "nofollow" or "none" is specified.
+func (r *Robots) NoFollow() bool {
+	return r.Has("nofollow") || r.Has("none")
+}
+
+// Has returns if given directive is specified, case-insensitively.
+func (r *Robots) Has(directive string) bool {
+	if r == nil {
+		return false
+	}
+	for _, d := range r.Directives {
+		if strings.EqualFold(d, directive) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Meta) contributeRobots(og *OpenGraph) {
+	if og.Robots == nil {
+		og.Robots = &Robots{}
+	}
+	for _, d := range strings.Split(m.Content, ",") {
+		if d = strings.ToLower(strings.TrimSpace(d)); d != "" {
+			og.Robots.Directives = append(og.Robots.Directives, d)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package opengraph
+
+const (
+	// ProvenanceApplicationName is a Provenance of fields filled by <meta name="application-name">.
+	ProvenanceApplicationName = "application-name"
+	// ProvenanceHost is a Provenance of fields filled by the host of og.URL.
+	ProvenanceHost = "host"
+)
+
+// fallbackSiteName fills SiteName by <meta name="application-name">, or the host of og.URL
+// including the port as Fulfill does, if og:site_name is not specified.
+func (og *OpenGraph) fallbackSiteName() {
+	if og.SiteName != "" || og.Policy.Strict {
+		return
+	}
+	if og.applicationName != "" {
+		og.SiteName = og.applicationName
+		og.provide("SiteName", ProvenanceApplicationName)
+		return
+	}
+	if og.URL.URL != nil && og.URL.Host != "" {
+		og.SiteName = og.URL.Host
+		og.provide("SiteName", ProvenanceHost)
+	}
+}
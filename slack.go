@@ -0,0 +1,49 @@
+package opengraph
+
+import "strings"
+
+// SlackUnfurl represents fields of Slack message attachment to unfurl a link.
+type SlackUnfurl struct {
+	Title     string `json:"title,omitempty"`
+	TitleLink string `json:"title_link,omitempty"`
+	Text      string `json:"text,omitempty"`
+	ImageURL  string `json:"image_url,omitempty"`
+	ThumbURL  string `json:"thumb_url,omitempty"`
+}
+
+// SlackUnfurl returns SlackUnfurl of og, with absolute URLs.
+// TitleLink is og:url, or the URL of og if og:url is absent.
+// ImageURL is the first og:image available over https, preferring og:image:secure_url,
+// and ThumbURL is Favicon if it's https. Insecure URLs are left empty since Slack may not show them.
+func (og *OpenGraph) SlackUnfurl() SlackUnfurl {
+	unfurl := SlackUnfurl{
+		Title: og.Title,
+		Text:  og.Description,
+	}
+	if og.URL.Value != "" {
+		unfurl.TitleLink = og.abs(og.URL.Value)
+	} else if og.URL.URL != nil {
+		unfurl.TitleLink = og.URL.String()
+	}
+	for _, img := range og.Image {
+		if img == nil {
+			continue
+		}
+		if u := og.abs(img.SURL); img.SURL != "" && isHTTPS(u) {
+			unfurl.ImageURL = u
+			break
+		}
+		if u := og.abs(img.URL); isHTTPS(u) {
+			unfurl.ImageURL = u
+			break
+		}
+	}
+	if u := og.abs(og.Favicon); og.Favicon != "" && isHTTPS(u) {
+		unfurl.ThumbURL = u
+	}
+	return unfurl
+}
+
+func isHTTPS(rawurl string) bool {
+	return strings.HasPrefix(strings.ToLower(rawurl), "https://")
+}
@@ -0,0 +1,15 @@
+package opengraph
+
+import "time"
+
+// ParseStats represents statistics of the last Parse, collected if Policy.CollectStats is set.
+type ParseStats struct {
+	// Nodes is the number of HTML nodes visited.
+	Nodes int
+	// Metas is the number of <meta> contributed.
+	Metas int
+	// Images is the number of og:image found.
+	Images int
+	// Duration is how long it took to parse and walk the document.
+	Duration time.Duration
+}
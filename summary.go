@@ -0,0 +1,72 @@
+package opengraph
+
+// SiteSummary represents informations aggregated over pages of a site.
+type SiteSummary struct {
+	// Pages is the number of non-nil pages summarized.
+	Pages int
+	// SiteName, Locale and Type are the most frequent non-empty values,
+	// and the first seen one wins a tie.
+	SiteName string
+	Locale   string
+	Type     string
+	// Images are all distinct image URLs in order of appearance.
+	Images []string
+}
+
+// Summarize aggregates given pages, such as results of FetchAll, into SiteSummary.
+// Nil entries are ignored.
+func Summarize(pages []*OpenGraph) *SiteSummary {
+	summary := &SiteSummary{Images: []string{}}
+	siteNames, locales, types := newCounter(), newCounter(), newCounter()
+	seen := map[string]bool{}
+	for _, og := range pages {
+		if og == nil {
+			continue
+		}
+		summary.Pages++
+		siteNames.add(og.SiteName)
+		locales.add(string(og.Locale))
+		types.add(string(og.Type))
+		for _, img := range og.Image {
+			if img == nil || img.URL == "" || seen[img.URL] {
+				continue
+			}
+			seen[img.URL] = true
+			summary.Images = append(summary.Images, img.URL)
+		}
+	}
+	summary.SiteName = siteNames.mostCommon()
+	summary.Locale = locales.mostCommon()
+	summary.Type = types.mostCommon()
+	return summary
+}
+
+// counter counts non-empty values keeping the order of first appearance.
+type counter struct {
+	order  []string
+	counts map[string]int
+}
+
+func newCounter() *counter {
+	return &counter{counts: map[string]int{}}
+}
+
+func (c *counter) add(value string) {
+	if value == "" {
+		return
+	}
+	if c.counts[value] == 0 {
+		c.order = append(c.order, value)
+	}
+	c.counts[value]++
+}
+
+func (c *counter) mostCommon() string {
+	best := ""
+	for _, value := range c.order {
+		if c.counts[value] > c.counts[best] {
+			best = value
+		}
+	}
+	return best
+}
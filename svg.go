@@ -0,0 +1,60 @@
+package opengraph
+
+import (
+	"encoding/xml"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// svgDimensions reads width and height of the root <svg> element,
+// or the size of its viewBox if either is absent or relative such as "100%".
+// It returns false if neither is available.
+func svgDimensions(r io.Reader) (width, height int, ok bool) {
+	d := xml.NewDecoder(r)
+	d.Strict = false
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return 0, 0, false
+		}
+		start, isStart := token.(xml.StartElement)
+		if !isStart {
+			continue
+		}
+		if start.Name.Local != "svg" {
+			return 0, 0, false
+		}
+		var viewBox string
+		for _, attr := range start.Attr {
+			switch attr.Name.Local {
+			case "width":
+				width = svgLength(attr.Value)
+			case "height":
+				height = svgLength(attr.Value)
+			case "viewBox":
+				viewBox = attr.Value
+			}
+		}
+		if width > 0 && height > 0 {
+			return width, height, true
+		}
+		fields := strings.FieldsFunc(viewBox, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r' })
+		if len(fields) != 4 {
+			return 0, 0, false
+		}
+		width, height = svgLength(fields[2]), svgLength(fields[3])
+		return width, height, width > 0 && height > 0
+	}
+}
+
+// svgLength parses a length in user units or pixels, e.g. "1200" or "1200px", rounded to int.
+// It returns 0 for other units.
+func svgLength(value string) int {
+	f, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(value), "px"), 64)
+	if err != nil || f <= 0 {
+		return 0
+	}
+	return int(math.Round(f))
+}
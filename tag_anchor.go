@@ -0,0 +1,54 @@
+package opengraph
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Anchor represents any "<a ...>" HTML tag.
+type Anchor struct {
+	Href string
+}
+
+// AnchorTag constructs Anchor.
+func AnchorTag(n *html.Node) *Anchor {
+	a := new(Anchor)
+	for _, attr := range n.Attr {
+		if attr.Key == "href" {
+			a.Href = strings.TrimSpace(attr.Val)
+		}
+	}
+	return a
+}
+
+// Contribute contributes to OpenGraph
+func (a *Anchor) Contribute(og *OpenGraph) error {
+	if !a.IsLink() {
+		return nil
+	}
+	href := og.abs(a.Href)
+	if og.links[href] {
+		return nil
+	}
+	if og.links == nil {
+		og.links = map[string]bool{}
+	}
+	og.links[href] = true
+	og.Links = append(og.Links, href)
+	return nil
+}
+
+// IsLink returns if it can be a link to another document
+func (a *Anchor) IsLink() bool {
+	if a.Href == "" || strings.HasPrefix(a.Href, "#") {
+		return false
+	}
+	scheme := strings.ToLower(a.Href)
+	for _, s := range []string{"javascript:", "mailto:", "tel:"} {
+		if strings.HasPrefix(scheme, s) {
+			return false
+		}
+	}
+	return true
+}
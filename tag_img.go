@@ -0,0 +1,144 @@
+package opengraph
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// ProvenanceBody is a Provenance of fields guessed from the document body.
+const ProvenanceBody = "body"
+
+// Img represents any "<img ...>" HTML tag.
+type Img struct {
+	Src    string
+	Srcset string
+	// Width and Height are of the attributes, or of pixel values in style attribute if absent.
+	// They are 0 if unknown.
+	Width  int
+	Height int
+}
+
+// ImgTag constructs Img.
+func ImgTag(n *html.Node) *Img {
+	img := new(Img)
+	var style string
+	for _, attr := range n.Attr {
+		switch attr.Key {
+		case "src":
+			img.Src = strings.TrimSpace(attr.Val)
+		case "srcset":
+			img.Srcset = attr.Val
+		case "width":
+			img.Width = imgDimension(attr.Val)
+		case "height":
+			img.Height = imgDimension(attr.Val)
+		case "style":
+			style = attr.Val
+		}
+	}
+	if img.Width == 0 && img.Height == 0 && style != "" {
+		img.Width, img.Height = styleDimensions(style)
+	}
+	return img
+}
+
+// imgDimension parses width or height attribute of <img>, which is a non-negative integer.
+func imgDimension(value string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// styleDimensions reads width and height of pixels from inline style, e.g. "width:1200px;height:630px".
+// Values of other units such as "%" or "em" are ignored as 0.
+func styleDimensions(style string) (width, height int) {
+	for _, decl := range strings.Split(style, ";") {
+		i := strings.Index(decl, ":")
+		if i < 0 {
+			continue
+		}
+		value := strings.ToLower(strings.TrimSpace(decl[i+1:]))
+		if !strings.HasSuffix(value, "px") {
+			continue
+		}
+		f, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(value, "px")), 64)
+		if err != nil || f <= 0 {
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(decl[:i])) {
+		case "width":
+			width = int(math.Round(f))
+		case "height":
+			height = int(math.Round(f))
+		}
+	}
+	return width, height
+}
+
+// Contribute contributes to OpenGraph
+func (img *Img) Contribute(og *OpenGraph) error {
+	if og.bodyImage != nil {
+		return nil
+	}
+	source := img.Source()
+	// A rejected image such as a data URI placeholder gives way to the next <img>.
+	if source == "" || og.rejectsImageURL("og:image", source) {
+		return nil
+	}
+	og.bodyImage = &OGImage{URL: source}
+	// Dimensions describe src, not candidates of srcset.
+	if source == img.Src {
+		og.bodyImage.Width, og.bodyImage.Height = img.Width, img.Height
+	}
+	return nil
+}
+
+// Source returns the highest-resolution candidate of srcset, or src if srcset is empty.
+// "w" descriptors are preferred to "x" descriptors, and a candidate without descriptor is "1x".
+func (img *Img) Source() string {
+	var best string
+	var bestW, bestX float64
+	for _, candidate := range strings.Split(img.Srcset, ",") {
+		fields := strings.Fields(candidate)
+		if len(fields) == 0 {
+			continue
+		}
+		descriptor := "1x"
+		if len(fields) > 1 {
+			descriptor = fields[1]
+		}
+		n, err := strconv.ParseFloat(descriptor[:len(descriptor)-1], 64)
+		if err != nil {
+			continue
+		}
+		switch descriptor[len(descriptor)-1] {
+		case 'w':
+			if n > bestW {
+				best, bestW = fields[0], n
+			}
+		case 'x':
+			if bestW == 0 && n > bestX {
+				best, bestX = fields[0], n
+			}
+		}
+	}
+	if best == "" {
+		return img.Src
+	}
+	return best
+}
+
+// guessImageFromBody adds the first <img> in the document as og:image if there is no og:image.
+func (og *OpenGraph) guessImageFromBody() {
+	if og.bodyImage == nil || len(og.Image) != 0 || og.Policy.Strict {
+		return
+	}
+	og.bodyImage.URL = og.abs(og.bodyImage.URL)
+	og.Image = append(og.Image, og.bodyImage)
+	og.provide("Image", ProvenanceBody)
+}
@@ -1,11 +1,25 @@
 package opengraph
 
-import "golang.org/x/net/html"
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
 
 // Link represents any "<link ...>" HTML tag
 type Link struct {
-	Rel  string
-	Href string
+	Rel      string
+	Href     string
+	Hreflang string
+	Type     string
+	Title    string
+}
+
+// Feed represents RSS or Atom feed declared by <link rel="alternate">.
+type Feed struct {
+	Title string
+	Href  string
+	Type  string
 }
 
 // LinkTag constructs Link
@@ -16,7 +30,13 @@ func LinkTag(n *html.Node) *Link {
 		case "rel":
 			link.Rel = attr.Val
 		case "href":
-			link.Href = attr.Val
+			link.Href = trimURL(attr.Val)
+		case "hreflang":
+			link.Hreflang = attr.Val
+		case "type":
+			link.Type = attr.Val
+		case "title":
+			link.Title = attr.Val
 		}
 	}
 	return link
@@ -26,19 +46,66 @@ func LinkTag(n *html.Node) *Link {
 func (link *Link) Contribute(og *OpenGraph) error {
 	switch {
 	case link.IsFavicon():
+		if og.rejectsScheme("favicon", link.Href) {
+			return nil
+		}
 		og.Favicon = link.Href
+		if og.faviconOnly {
+			og.done = true
+		}
 	case link.IsCanonical():
+		if og.rejectsScheme("canonical", link.Href) {
+			return nil
+		}
 		og.CanonicalURL = link.Href
+	case link.IsFeed():
+		if og.rejectsScheme("feed", link.Href) {
+			return nil
+		}
+		og.Feeds = append(og.Feeds, Feed{Title: link.Title, Href: og.abs(link.Href), Type: link.Type})
+	case link.IsManifest():
+		if og.ManifestURL == "" && !og.rejectsScheme("manifest", link.Href) {
+			og.ManifestURL = og.abs(link.Href)
+		}
+	case link.IsLocaleAlternate():
+		og.alternates = append(og.alternates, link)
 	}
 	return nil
 }
 
+// HasRel returns if rel, a space-separated list such as "shortcut icon", contains given token,
+// ignoring case.
+func (link *Link) HasRel(token string) bool {
+	for _, rel := range strings.Fields(link.Rel) {
+		if strings.EqualFold(rel, token) {
+			return true
+		}
+	}
+	return false
+}
+
 // IsFavicon returns if it can be "favicon" of *opengraph.OpenGraph
 func (link *Link) IsFavicon() bool {
-	return link.Rel == "shortcut icon" || link.Rel == "icon"
+	return link.HasRel("icon")
 }
 
 // IsCanonical returns if it can be "canonical" of *opengraph.OpenGraph
 func (link *Link) IsCanonical() bool {
-	return link.Rel == "canonical"
+	return link.HasRel("canonical")
+}
+
+// IsManifest returns if it can be the web app manifest of the page
+func (link *Link) IsManifest() bool {
+	return link.HasRel("manifest") && link.Href != ""
+}
+
+// IsLocaleAlternate returns if it can be a localized alternate of the page
+func (link *Link) IsLocaleAlternate() bool {
+	return link.HasRel("alternate") && link.Hreflang != "" && link.Href != ""
+}
+
+// IsFeed returns if it can be RSS or Atom feed of the page
+func (link *Link) IsFeed() bool {
+	return link.HasRel("alternate") && link.Href != "" &&
+		(link.Type == "application/rss+xml" || link.Type == "application/atom+xml")
 }
@@ -3,15 +3,18 @@ package opengraph
 import (
 	"strconv"
 	"strings"
+	"time"
 
 	"golang.org/x/net/html"
 )
 
 // Meta represents any "<meta ...>" HTML tag.
 type Meta struct {
-	Name     string
-	Property string
-	Content  string
+	Name      string
+	Property  string
+	Content   string
+	Media     string
+	HTTPEquiv string
 }
 
 // MetaTag constructs MetaTag.
@@ -25,6 +28,10 @@ func MetaTag(n *html.Node) *Meta {
 			m.Content = attr.Val
 		case "name":
 			m.Name = attr.Val
+		case "media":
+			m.Media = attr.Val
+		case "http-equiv":
+			m.HTTPEquiv = attr.Val
 		}
 	}
 	return m
@@ -32,35 +39,341 @@ func MetaTag(n *html.Node) *Meta {
 
 // Contribute ...
 func (m *Meta) Contribute(og *OpenGraph) error {
+	if strings.HasPrefix(m.Property, "og:") {
+		og.properties++
+	}
+	if og.Policy.KeepRaw || len(og.Policy.CapturePrefixes) != 0 {
+		m.keepRaw(og)
+	}
+	if og.Policy.IgnoreEmptyValues && strings.TrimSpace(m.Content) == "" {
+		return nil
+	}
+	if m.Name == "twitter:card" || m.Property == "twitter:card" {
+		og.Capabilities.HasTwitterCard = true
+	}
 	switch {
 	case m.IsTitle():
-		og.Title = m.Content
+		og.assign(m.Property, &og.Title, m.Content)
+		if og.titleOnly {
+			og.done = true
+		}
 	case m.IsOGDescription():
+		og.assign(m.Property, &og.Description, m.Content)
+	case m.IsDescription() && og.Description == "" && !og.Policy.Strict:
 		og.Description = m.Content
-	case m.IsDescription() && og.Description == "":
-		og.Description = m.Content
+	case m.IsAuthor() && !og.Policy.Strict:
+		og.authors = append(og.authors, m.Content)
+	case m.IsApplicationName() && og.applicationName == "" && !og.Policy.Strict:
+		og.applicationName = m.Content
+	case m.IsThemeColor() && og.ThemeColor == "" && !og.Policy.Strict:
+		og.ThemeColor = m.Content
 	case m.IsImage():
-		og.Image = append(og.Image, &OGImage{URL: m.Content})
+		if og.continuesImage(m.Property, trimURL(m.Content)) {
+			return nil
+		}
+		if og.exceeds("og:image", len(og.Image), og.Policy.MaxImages) || og.rejectsImageURL("og:image", m.Content) {
+			og.drop("og:image")
+			og.pendingImage = nil
+			return nil
+		}
+		og.accept("og:image")
+		img := &OGImage{}
+		if og.pendingImage != nil {
+			img, og.pendingImage = og.pendingImage, nil
+		}
+		img.URL = trimURL(m.Content)
+		og.Image = append(og.Image, img)
+		og.imageRoot = m.Property
 	case m.IsSiteName():
-		og.SiteName = m.Content
+		og.assign(m.Property, &og.SiteName, m.Content)
 	case m.IsImageProperty():
-		if len(og.Image) == 0 {
+		img := og.currentImage()
+		if img == nil {
 			return nil
 		}
+		og.Capabilities.HasStructuredImages = true
 		switch m.Property {
+		case "og:image:secure_url":
+			if og.rejectsScheme(m.Property, m.Content) || og.rejectsImageHost(m.Property, m.Content) {
+				return nil
+			}
+			og.duplicated(m.Property, img.SURL, trimURL(m.Content))
+			img.SURL = trimURL(m.Content)
 		case "og:image:width":
-			og.Image[len(og.Image)-1].Width, _ = strconv.Atoi(m.Content)
+			if og.Policy.ImageRenditions && (img.Width != 0 || len(img.Renditions) != 0) {
+				img.addRendition(og.dimension(m.Property, m.Content), 0)
+				return nil
+			}
+			og.duplicatedInt(m.Property, img.Width, m.Content)
+			img.Width = og.dimension(m.Property, m.Content)
 		case "og:image:height":
-			og.Image[len(og.Image)-1].Height, _ = strconv.Atoi(m.Content)
+			if og.Policy.ImageRenditions && (img.Height != 0 || len(img.Renditions) != 0) {
+				img.addRendition(0, og.dimension(m.Property, m.Content))
+				return nil
+			}
+			og.duplicatedInt(m.Property, img.Height, m.Content)
+			img.Height = og.dimension(m.Property, m.Content)
+		case "og:image:type":
+			og.duplicated(m.Property, img.Type, m.Content)
+			img.Type = m.Content
+		case "og:image:alt":
+			og.duplicated(m.Property, img.Alt, m.Content)
+			img.Alt = m.Content
+		case "og:image:user_generated":
+			img.UserGenerated = m.Content == "true"
+		}
+	case m.IsVideo():
+		if og.continuesVideo(m.Property, trimURL(m.Content)) {
+			return nil
+		}
+		if og.exceeds("og:video", len(og.Video), og.Policy.MaxVideos) || og.rejectsScheme("og:video", m.Content) {
+			og.drop("og:video")
+			return nil
+		}
+		og.accept("og:video")
+		og.Video = append(og.Video, &OGVideo{URL: trimURL(m.Content)})
+		og.videoRoot = m.Property
+	case m.IsVideoProperty():
+		if len(og.Video) == 0 || og.dropped["og:video"] {
+			return nil
+		}
+		video := og.Video[len(og.Video)-1]
+		switch m.Property {
+		case "og:video:secure_url":
+			if og.rejectsScheme(m.Property, m.Content) {
+				return nil
+			}
+			og.duplicated(m.Property, video.SURL, trimURL(m.Content))
+			video.SURL = trimURL(m.Content)
+		case "og:video:width":
+			og.duplicatedInt(m.Property, video.Width, m.Content)
+			video.Width = og.dimension(m.Property, m.Content)
+		case "og:video:height":
+			og.duplicatedInt(m.Property, video.Height, m.Content)
+			video.Height = og.dimension(m.Property, m.Content)
+		case "og:video:type":
+			og.duplicated(m.Property, video.Type, m.Content)
+			video.Type = m.Content
+		}
+	case m.IsAudio():
+		if og.exceeds("og:audio", len(og.Audio), og.Policy.MaxAudios) || og.rejectsScheme("og:audio", m.Content) {
+			og.drop("og:audio")
+			return nil
+		}
+		og.accept("og:audio")
+		og.Audio = append(og.Audio, &OGAudio{URL: trimURL(m.Content)})
+	case m.IsAudioProperty():
+		if len(og.Audio) == 0 || og.dropped["og:audio"] {
+			return nil
+		}
+		audio := og.Audio[len(og.Audio)-1]
+		switch m.Property {
+		case "og:audio:secure_url":
+			if og.rejectsScheme(m.Property, m.Content) {
+				return nil
+			}
+			og.duplicated(m.Property, audio.SURL, trimURL(m.Content))
+			audio.SURL = trimURL(m.Content)
+		case "og:audio:type":
+			og.duplicated(m.Property, audio.Type, m.Content)
+			audio.Type = m.Content
 		}
 	case m.IsType():
-		og.Type = m.Content
+		og.assign(m.Property, (*string)(&og.Type), m.Content)
 	case m.IsURL():
-		og.URL.Value = m.Content
+		if og.rejectsScheme(m.Property, m.Content) {
+			return nil
+		}
+		og.assign(m.Property, &og.URL.Value, trimURL(m.Content))
+		og.urls = append(og.urls, trimURL(m.Content))
+	case m.IsLocale():
+		og.assign(m.Property, (*string)(&og.Locale), m.Content)
+	case m.IsDeterminer():
+		og.assign(m.Property, (*string)(&og.Determiner), m.Content)
+	case m.IsLocaleAlternate():
+		og.LocaleAlt = append(og.LocaleAlt, og.localeAlternates(m.Content)...)
+	case m.IsRestrictionsProperty():
+		m.contributeRestrictions(og)
+	case m.IsProductProperty():
+		m.contributeProduct(og)
+	case m.IsVideoObjectProperty():
+		m.contributeVideoObject(og)
+	case m.IsTTL():
+		if sec, err := strconv.Atoi(m.Content); err == nil && sec > 0 {
+			og.ttl = time.Duration(sec) * time.Second
+		}
+	case m.IsRefresh():
+		og.refresh = refreshURL(m.Content)
+	case m.IsArticleProperty():
+		m.contributeArticle(og)
+	case m.IsFacebookProperty():
+		m.contributeFacebook(og)
+	case m.IsRobots():
+		m.contributeRobots(og)
+	case m.IsMobile() && !og.Policy.Strict:
+		m.contributeMobile(og)
 	}
 	return nil
 }
 
+func (m *Meta) contributeArticle(og *OpenGraph) {
+	if og.Article == nil {
+		og.Article = &OGArticle{}
+	}
+	switch m.Property {
+	case "article:published_time":
+		og.Article.PublishedTime = m.Content
+		og.Article.Published, _ = og.parseDate(m.Content)
+	case "article:modified_time":
+		og.Article.ModifiedTime = m.Content
+		og.Article.Modified, _ = og.parseDate(m.Content)
+	case "article:expiration_time":
+		og.Article.ExpirationTime = m.Content
+		og.Article.Expiration, _ = og.parseDate(m.Content)
+	case "article:author":
+		og.Article.Author = append(og.Article.Author, m.Content)
+	case "article:section":
+		og.Article.Section = m.Content
+	case "article:tag":
+		og.Article.Tag = append(og.Article.Tag, m.Content)
+	}
+}
+
+func (m *Meta) contributeRestrictions(og *OpenGraph) {
+	if og.Restrictions == nil {
+		og.Restrictions = &OGRestrictions{}
+	}
+	switch m.Property {
+	case "og:restrictions:age":
+		og.Restrictions.Age = m.Content
+	case "og:restrictions:country:allowed":
+		og.Restrictions.CountryAllowed = append(og.Restrictions.CountryAllowed, m.Content)
+	case "og:restrictions:country:disallowed":
+		og.Restrictions.CountryDisallowed = append(og.Restrictions.CountryDisallowed, m.Content)
+	case "og:restrictions:content":
+		og.Restrictions.Content = append(og.Restrictions.Content, m.Content)
+	}
+}
+
+// duplicated warns a property of the current structure declared again with a different value,
+// as assign does for scalar properties.
+func (og *OpenGraph) duplicated(property, current, value string) {
+	if current != "" && current != value {
+		og.warnDuplicate(property, current, value)
+	}
+}
+
+// duplicatedInt is duplicated for width and height.
+func (og *OpenGraph) duplicatedInt(property string, current int, value string) {
+	if n, err := strconv.Atoi(strings.TrimSpace(value)); current != 0 && (err != nil || n != current) {
+		og.warnDuplicate(property, strconv.Itoa(current), value)
+	}
+}
+
+// dimension parses width or height, and regards non-positive ones as unknown with a warning.
+func (og *OpenGraph) dimension(property, value string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0
+	}
+	if n <= 0 {
+		og.warn(property, "non-positive dimension %d is ignored", n)
+		return 0
+	}
+	return n
+}
+
+func (m *Meta) keepRaw(og *OpenGraph) {
+	key := m.Property
+	if key == "" {
+		key = m.Name
+	}
+	if key == "" || !og.captures(key) {
+		return
+	}
+	if og.Raw == nil {
+		og.Raw = map[string][]string{}
+	}
+	og.Raw[key] = append(og.Raw[key], m.Content)
+}
+
+// captures returns if given key should be kept in og.Raw according to Policy.CapturePrefixes.
+func (og *OpenGraph) captures(key string) bool {
+	if len(og.Policy.CapturePrefixes) == 0 {
+		return true
+	}
+	for _, prefix := range og.Policy.CapturePrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Meta) contributeProduct(og *OpenGraph) {
+	if og.Product == nil {
+		og.Product = &OGProduct{}
+	}
+	switch strings.TrimPrefix(strings.TrimPrefix(m.Property, "product:"), "og:") {
+	case "price:amount":
+		og.Product.PriceAmount = m.Content
+	case "price:currency":
+		og.Product.PriceCurrency = m.Content
+	case "availability":
+		og.Product.Availability = m.Content
+	case "condition":
+		og.Product.Condition = m.Content
+	case "brand":
+		og.Product.Brand = m.Content
+	case "retailer_item_id":
+		og.Product.RetailerItemID = m.Content
+	}
+}
+
+func (m *Meta) contributeVideoObject(og *OpenGraph) {
+	if og.VideoObject == nil {
+		og.VideoObject = &OGVideoObject{}
+	}
+	v := og.VideoObject
+	switch m.Property {
+	case "video:actor":
+		v.Actors = append(v.Actors, OGActor{Profile: m.Content})
+	case "video:actor:role":
+		if len(v.Actors) != 0 {
+			v.Actors[len(v.Actors)-1].Role = m.Content
+		}
+	case "video:director":
+		v.Directors = append(v.Directors, m.Content)
+	case "video:writer":
+		v.Writers = append(v.Writers, m.Content)
+	case "video:duration":
+		v.Duration, _ = strconv.Atoi(m.Content)
+	case "video:release_date":
+		v.RawReleaseDate = m.Content
+		v.ReleaseDate, _ = og.parseDate(m.Content)
+	case "video:tag":
+		v.Tags = append(v.Tags, m.Content)
+	case "video:series":
+		v.Series = m.Content
+	}
+}
+
+func (m *Meta) contributeFacebook(og *OpenGraph) {
+	if og.Facebook == nil {
+		og.Facebook = &Facebook{}
+	}
+	switch m.Property {
+	case "fb:app_id":
+		og.Facebook.AppID = m.Content
+	case "fb:admins":
+		for _, admin := range strings.Split(m.Content, ",") {
+			if admin = strings.TrimSpace(admin); admin != "" {
+				og.Facebook.Admins = append(og.Facebook.Admins, admin)
+			}
+		}
+	}
+}
+
 // IsTitle returns if it can be "title" of OGP
 func (m *Meta) IsTitle() bool {
 	return m.Property == "og:title" && m.Content != ""
@@ -76,6 +389,21 @@ func (m *Meta) IsDescription() bool {
 	return m.Name == "description" && m.Content != ""
 }
 
+// IsAuthor returns if it can be "author" of the document
+func (m *Meta) IsAuthor() bool {
+	return m.Name == "author" && m.Content != ""
+}
+
+// IsApplicationName returns if it can be "application-name" of name
+func (m *Meta) IsApplicationName() bool {
+	return m.Name == "application-name" && m.Content != ""
+}
+
+// IsThemeColor returns if it can be "theme-color" without media query
+func (m *Meta) IsThemeColor() bool {
+	return m.Name == "theme-color" && m.Media == "" && m.Content != ""
+}
+
 // IsImage returns if it can be a root of "og:image"
 func (m *Meta) IsImage() bool {
 	return m.Property == "og:image" || m.Property == "og:image:url"
@@ -86,6 +414,26 @@ func (m *Meta) IsImageProperty() bool {
 	return strings.HasPrefix(m.Property, "og:image:")
 }
 
+// IsVideo returns if it can be a root of "og:video"
+func (m *Meta) IsVideo() bool {
+	return m.Property == "og:video" || m.Property == "og:video:url"
+}
+
+// IsVideoProperty returns if it can be a property of "og:video" struct
+func (m *Meta) IsVideoProperty() bool {
+	return strings.HasPrefix(m.Property, "og:video:")
+}
+
+// IsAudio returns if it can be a root of "og:audio"
+func (m *Meta) IsAudio() bool {
+	return m.Property == "og:audio" || m.Property == "og:audio:url"
+}
+
+// IsAudioProperty returns if it can be a property of "og:audio" struct
+func (m *Meta) IsAudioProperty() bool {
+	return strings.HasPrefix(m.Property, "og:audio:")
+}
+
 // IsType returns if it can be "og:type"
 func (m *Meta) IsType() bool {
 	return m.Property == "og:type"
@@ -96,6 +444,73 @@ func (m *Meta) IsSiteName() bool {
 	return m.Property == "og:site_name"
 }
 
+// IsLocale returns if it can be "og:locale"
+func (m *Meta) IsLocale() bool {
+	return m.Property == "og:locale"
+}
+
+// IsDeterminer returns if it can be "og:determiner"
+func (m *Meta) IsDeterminer() bool {
+	return m.Property == "og:determiner" && m.Content != ""
+}
+
+// IsLocaleAlternate returns if it can be "og:locale:alternate"
+func (m *Meta) IsLocaleAlternate() bool {
+	return m.Property == "og:locale:alternate" && m.Content != ""
+}
+
+// IsRestrictionsProperty returns if it can be a property of "og:restrictions:*" struct
+func (m *Meta) IsRestrictionsProperty() bool {
+	return strings.HasPrefix(m.Property, "og:restrictions:") && m.Content != ""
+}
+
+// IsProductProperty returns if it can be a property of "product:*" struct
+func (m *Meta) IsProductProperty() bool {
+	if m.Content == "" {
+		return false
+	}
+	switch m.Property {
+	case "og:price:amount", "og:price:currency", "og:availability":
+		return true
+	}
+	return strings.HasPrefix(m.Property, "product:")
+}
+
+// IsVideoObjectProperty returns if it can be a property of "video:*" struct
+func (m *Meta) IsVideoObjectProperty() bool {
+	return strings.HasPrefix(m.Property, "video:") && m.Content != ""
+}
+
+// IsTTL returns if it can be "og:ttl", seconds to cache the page
+func (m *Meta) IsTTL() bool {
+	return m.Property == "og:ttl" && m.Content != ""
+}
+
+// IsRefresh returns if it can be "refresh" of http-equiv
+func (m *Meta) IsRefresh() bool {
+	return strings.EqualFold(m.HTTPEquiv, "refresh")
+}
+
+// IsArticleProperty returns if it can be a property of "article:*" struct
+func (m *Meta) IsArticleProperty() bool {
+	return strings.HasPrefix(m.Property, "article:")
+}
+
+// IsFacebookProperty returns if it can be a property of "fb:*" struct
+func (m *Meta) IsFacebookProperty() bool {
+	return strings.HasPrefix(m.Property, "fb:")
+}
+
+// IsRobots returns if it can be "robots" or "googlebot" of name
+func (m *Meta) IsRobots() bool {
+	return (strings.EqualFold(m.Name, "robots") || strings.EqualFold(m.Name, "googlebot")) && m.Content != ""
+}
+
+// IsMobile returns if it can be "viewport" or "apple-mobile-web-app-capable" of name
+func (m *Meta) IsMobile() bool {
+	return (strings.EqualFold(m.Name, "viewport") || strings.EqualFold(m.Name, "apple-mobile-web-app-capable")) && m.Content != ""
+}
+
 // IsURL returns if it can be "og:url"
 func (m *Meta) IsURL() bool {
 	return m.Property == "og:url"
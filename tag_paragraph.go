@@ -0,0 +1,69 @@
+package opengraph
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	// HTMLParagraphTag is a tag name of <p>
+	HTMLParagraphTag string = "p"
+
+	// minBodyDescriptionRunes is the minimum length of <p> to be a description,
+	// to skip tiny or navigation paragraphs.
+	minBodyDescriptionRunes = 40
+	// maxBodyDescriptionRunes is the length which a description from <p> is truncated to.
+	maxBodyDescriptionRunes = 300
+)
+
+// Paragraph represents any "<p>" HTML tag.
+type Paragraph struct {
+	Text string
+}
+
+// ParagraphTag constructs Paragraph with its text content, whitespaces collapsed.
+func ParagraphTag(n *html.Node) *Paragraph {
+	b := new(strings.Builder)
+	var collect func(*html.Node)
+	collect = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+			b.WriteString(" ")
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			collect(c)
+		}
+	}
+	collect(n)
+	return &Paragraph{Text: strings.Join(strings.Fields(b.String()), " ")}
+}
+
+// Contribute contributes to OpenGraph
+func (p *Paragraph) Contribute(og *OpenGraph) error {
+	if og.bodyDescription == "" && utf8.RuneCountInString(p.Text) >= minBodyDescriptionRunes {
+		og.bodyDescription = p.Text
+	}
+	return nil
+}
+
+// inNavigation returns if n is inside of <nav>, <header> or <footer>.
+func inNavigation(n *html.Node) bool {
+	for p := n.Parent; p != nil; p = p.Parent {
+		switch p.Data {
+		case "nav", "header", "footer":
+			return true
+		}
+	}
+	return false
+}
+
+// guessDescriptionFromBody uses the first substantive <p> as description if there is no description.
+func (og *OpenGraph) guessDescriptionFromBody() {
+	if og.bodyDescription == "" || og.Description != "" || og.Policy.Strict {
+		return
+	}
+	og.Description = truncate(og.bodyDescription, maxBodyDescriptionRunes)
+	og.provide("Description", ProvenanceBody)
+}
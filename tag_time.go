@@ -0,0 +1,44 @@
+package opengraph
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Time represents any "<time ...>" HTML tag.
+type Time struct {
+	Datetime string
+}
+
+// TimeTag constructs Time.
+func TimeTag(n *html.Node) *Time {
+	t := new(Time)
+	for _, attr := range n.Attr {
+		if attr.Key == "datetime" {
+			t.Datetime = strings.TrimSpace(attr.Val)
+		}
+	}
+	return t
+}
+
+// Contribute contributes to OpenGraph
+func (t *Time) Contribute(og *OpenGraph) error {
+	if og.bodyTime == "" {
+		og.bodyTime = t.Datetime
+	}
+	return nil
+}
+
+// guessDateFromBody uses the first <time datetime="..."> as article:published_time if it's not specified.
+func (og *OpenGraph) guessDateFromBody() {
+	if og.bodyTime == "" || og.Policy.Strict || (og.Article != nil && og.Article.PublishedTime != "") {
+		return
+	}
+	if og.Article == nil {
+		og.Article = &OGArticle{}
+	}
+	og.Article.PublishedTime = og.bodyTime
+	og.Article.Published, _ = og.parseDate(og.bodyTime)
+	og.provide("Article.PublishedTime", ProvenanceBody)
+}
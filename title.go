@@ -0,0 +1,27 @@
+package opengraph
+
+import (
+	"context"
+	"strings"
+)
+
+// FetchTitle fetches given page only to find its title, and returns og:title or <title> otherwise.
+// The page is parsed only until the first og:title or <body>, with charset handling of Fetch.
+// Use OpenGraph.FetchTitle to configure Policy beforehand.
+func FetchTitle(ctx context.Context, pageURL string) (string, error) {
+	og := New(pageURL)
+	og.Policy.TrustedTags = []string{HTMLMetaTag, HTMLTitleTag}
+	og.Policy.StopAtBody = true
+	return og.FetchTitle(ctx)
+}
+
+// FetchTitle fetches og.URL like Fetch but only until the first og:title,
+// and returns og:title or <title> otherwise.
+func (og *OpenGraph) FetchTitle(ctx context.Context) (string, error) {
+	og.titleOnly = true
+	defer func() { og.titleOnly = false }()
+	if err := og.Fetch(ctx); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(og.Title), nil
+}
@@ -0,0 +1,68 @@
+package opengraph
+
+import (
+	"net/url"
+	"strings"
+)
+
+// defaultTrackingParams are query parameters stripped by Policy.StripTrackingParams by default.
+// A name ending with "*" matches as a prefix.
+var defaultTrackingParams = []string{
+	"utm_*",
+	"fbclid",
+	"gclid",
+	"dclid",
+	"msclkid",
+	"yclid",
+	"igshid",
+	"mc_cid",
+	"mc_eid",
+	"_ga",
+}
+
+// stripTrackingParams removes tracking query parameters from given URL.
+// It returns raw as is if it can't be parsed or has no tracking parameter.
+func (og *OpenGraph) stripTrackingParams(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.RawQuery == "" {
+		return raw
+	}
+	// Pairs are filtered as they are, so that the rest keep their order and escaping.
+	pairs := strings.Split(u.RawQuery, "&")
+	kept := pairs[:0]
+	for _, pair := range pairs {
+		key := pair
+		if i := strings.Index(key, "="); i >= 0 {
+			key = key[:i]
+		}
+		if k, err := url.QueryUnescape(key); err == nil {
+			key = k
+		}
+		if !isTrackingParam(key, og.trackingParams()) {
+			kept = append(kept, pair)
+		}
+	}
+	if len(kept) == len(pairs) {
+		return raw
+	}
+	u.RawQuery = strings.Join(kept, "&")
+	return u.String()
+}
+
+// trackingParams returns Policy.TrackingParams, or defaultTrackingParams if not specified.
+func (og *OpenGraph) trackingParams() []string {
+	if len(og.Policy.TrackingParams) != 0 {
+		return og.Policy.TrackingParams
+	}
+	return defaultTrackingParams
+}
+
+func isTrackingParam(key string, params []string) bool {
+	key = strings.ToLower(key)
+	for _, p := range params {
+		if strings.HasSuffix(p, "*") && strings.HasPrefix(key, strings.TrimSuffix(p, "*")) || key == p {
+			return true
+		}
+	}
+	return false
+}
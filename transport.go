@@ -0,0 +1,70 @@
+package opengraph
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// transportOptions shapes the default transport, and is the key to share it.
+type transportOptions struct {
+	maxIdleConns int
+	disableHTTP2 bool
+	// network is "tcp4" or "tcp6" to restrict dialing, or empty.
+	network string
+}
+
+var (
+	transportsMu sync.Mutex
+	transports   = map[transportOptions]*http.Client{}
+)
+
+// httpClient returns og.HTTPClient if it's supplied by the caller.
+// Otherwise, i.e. it's nil or http.DefaultClient, it returns a client whose transport is shaped by
+// Policy.MaxIdleConns, Policy.ForceAttemptHTTP2, Policy.PreferIPv4 and Policy.PreferIPv6, shared by all OpenGraph with the same options
+// so that connections are reused across fetches. http.DefaultClient is used if neither is set.
+// The shaped clients are kept for the lifetime of the process, one per distinct set of the options,
+// so the options are not supposed to vary per fetch.
+func (og *OpenGraph) httpClient() *http.Client {
+	if og.HTTPClient != nil && og.HTTPClient != http.DefaultClient {
+		return og.HTTPClient
+	}
+	opts := transportOptions{maxIdleConns: og.Policy.MaxIdleConns}
+	if og.Policy.ForceAttemptHTTP2 != nil {
+		opts.disableHTTP2 = !*og.Policy.ForceAttemptHTTP2
+	}
+	switch {
+	case og.Policy.PreferIPv4:
+		opts.network = "tcp4"
+	case og.Policy.PreferIPv6:
+		opts.network = "tcp6"
+	}
+	if opts == (transportOptions{}) {
+		return http.DefaultClient
+	}
+	transportsMu.Lock()
+	defer transportsMu.Unlock()
+	if c, ok := transports[opts]; ok {
+		return c
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	// The clone attempts HTTP/2 as http.DefaultTransport does, unless opted out.
+	t.ForceAttemptHTTP2 = !opts.disableHTTP2
+	if opts.maxIdleConns > 0 {
+		t.MaxIdleConns = opts.maxIdleConns
+		t.MaxIdleConnsPerHost = opts.maxIdleConns
+	}
+	if opts.network != "" {
+		// Same as net.Dialer of http.DefaultTransport.
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+		network := opts.network
+		t.DialContext = func(ctx context.Context, _, addr string) (net.Conn, error) {
+			return dialer.DialContext(ctx, network, addr)
+		}
+	}
+	c := &http.Client{Transport: t}
+	transports[opts] = c
+	return c
+}
@@ -0,0 +1,61 @@
+package opengraph
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ellipsis is appended to truncated texts.
+const ellipsis = "…"
+
+// TruncatedDescription returns og.Description truncated to max runes including an ellipsis.
+func (og *OpenGraph) TruncatedDescription(max int) string {
+	return truncate(og.Description, max)
+}
+
+// TruncatedAlt returns og:image:alt truncated to max runes including an ellipsis.
+func (img *OGImage) TruncatedAlt(max int) string {
+	return truncate(img.Alt, max)
+}
+
+// truncate cuts s to at most max runes, without splitting multibyte characters,
+// and appends an ellipsis after trimming trailing spaces if it's cut.
+func truncate(s string, max int) string {
+	if max <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	runes := []rune(s)
+	return strings.TrimRight(string(runes[:max-1]), " \t\r\n") + ellipsis
+}
+
+// truncateTexts truncates Title and Description by Policy.MaxDescriptionRunes.
+func (og *OpenGraph) truncateTexts() {
+	if og.Policy.MaxDescriptionRunes <= 0 {
+		return
+	}
+	og.Title = truncateWords(og.Title, og.Policy.MaxDescriptionRunes)
+	og.Description = truncateWords(og.Description, og.Policy.MaxDescriptionRunes)
+}
+
+// truncateWords cuts s like truncate, but at the last space within max runes if any,
+// so that words are not split. Texts without spaces such as Japanese are cut by runes.
+func truncateWords(s string, max int) string {
+	t := truncate(s, max)
+	if t == s || t == "" {
+		return t
+	}
+	runes := []rune(strings.TrimSuffix(t, ellipsis))
+	if unicode.IsSpace([]rune(s)[len(runes)]) {
+		return t
+	}
+	for i := len(runes) - 1; i > 0; i-- {
+		if unicode.IsSpace(runes[i]) {
+			return strings.TrimRightFunc(string(runes[:i]), unicode.IsSpace) + ellipsis
+		}
+	}
+	return t
+}
@@ -0,0 +1,132 @@
+package opengraph
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ValidationErrors represents all errors found by Validate.
+type ValidationErrors []error
+
+func (errs ValidationErrors) Error() string {
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Validate checks if og has the required properties of OGP, which are og:title, og:type, og:image and og:url,
+// and if og:type is known by ogp.me or namespaced like "myapp:type".
+// It returns ValidationErrors if any.
+func (og *OpenGraph) Validate() error {
+	if errs := og.validate(); len(errs) != 0 {
+		return errs
+	}
+	return nil
+}
+
+func (og *OpenGraph) validate() ValidationErrors {
+	errs := ValidationErrors{}
+	if og.Title == "" {
+		errs = append(errs, fmt.Errorf("og:title is required"))
+	}
+	if og.Type == "" {
+		errs = append(errs, fmt.Errorf("og:type is required"))
+	} else if !og.Type.Valid() {
+		errs = append(errs, fmt.Errorf("og:type is unknown: %s", og.Type))
+	}
+	if len(og.Image) == 0 {
+		errs = append(errs, fmt.Errorf("og:image is required"))
+	}
+	if og.URL.Value == "" {
+		errs = append(errs, fmt.Errorf("og:url is required"))
+	}
+	return errs
+}
+
+// FetchAndValidate fetches the URL, makes URLs absolute and validates it.
+// Failure of fetching is returned as error, and validation errors are returned separately.
+// Use OpenGraph.FetchAndValidate to configure Policy beforehand.
+func FetchAndValidate(ctx context.Context, rawurl string) (*OpenGraph, []error, error) {
+	og := New(rawurl)
+	errs, err := og.FetchAndValidate(ctx)
+	return og, errs, err
+}
+
+// FetchAndValidate fetches og.URL like Fetch, makes URLs absolute and validates it.
+// Failure of fetching is returned as error, and validation errors are returned separately.
+func (og *OpenGraph) FetchAndValidate(ctx context.Context) ([]error, error) {
+	if err := og.Fetch(ctx); err != nil {
+		return nil, err
+	}
+	og.ToAbsURL()
+	return og.validate(), nil
+}
+
+// Severity represents how serious a ValidationIssue is.
+type Severity int
+
+const (
+	// SeverityError is for a violation of OGP, such as a missing required property.
+	SeverityError Severity = iota
+	// SeverityWarning is for a valid but incomplete document, such as an image without dimensions.
+	SeverityWarning
+	// SeverityInfo is for a notice, such as og:type defaulting to "website".
+	SeverityInfo
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "info"
+	default:
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+}
+
+// ValidationIssue represents an issue found by ValidationReport.
+type ValidationIssue struct {
+	Severity Severity
+	Field    string
+	Message  string
+}
+
+func (issue ValidationIssue) String() string {
+	return fmt.Sprintf("[%s] %s: %s", issue.Severity, issue.Field, issue.Message)
+}
+
+// ValidationReport checks og like Validate, and returns all issues with severity levels.
+// Unlike Validate, missing og:type is only an info because it defaults to "website",
+// and images without og:image:width or og:image:height are warned.
+func (og *OpenGraph) ValidationReport() []ValidationIssue {
+	issues := []ValidationIssue{}
+	add := func(severity Severity, field, format string, args ...interface{}) {
+		issues = append(issues, ValidationIssue{Severity: severity, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+	if og.Title == "" {
+		add(SeverityError, "og:title", "og:title is required")
+	}
+	if og.Type == "" {
+		add(SeverityInfo, "og:type", "og:type is not specified and defaults to website")
+	} else if !og.Type.Valid() {
+		add(SeverityError, "og:type", "og:type is unknown: %s", og.Type)
+	}
+	if len(og.Image) == 0 {
+		add(SeverityError, "og:image", "og:image is required")
+	}
+	for i, img := range og.Image {
+		if img.Width == 0 || img.Height == 0 {
+			add(SeverityWarning, fmt.Sprintf("og:image[%d]", i), "%s lacks og:image:width or og:image:height", img.URL)
+		}
+	}
+	if og.URL.Value == "" {
+		add(SeverityError, "og:url", "og:url is required")
+	}
+	return issues
+}
@@ -0,0 +1,209 @@
+package opengraph
+
+import (
+	"fmt"
+	"strings"
+)
+
+// knownTypes are og:type values defined by ogp.me.
+var knownTypes = []string{
+	"website",
+	"article",
+	"book",
+	"profile",
+	"music.song",
+	"music.album",
+	"music.playlist",
+	"music.radio_station",
+	"video.movie",
+	"video.episode",
+	"video.tv_show",
+	"video.other",
+}
+
+// extendedTypes are og:type values not defined by ogp.me but widely used,
+// such as "product" of Facebook for catalogs and rich pins.
+var extendedTypes = []string{
+	"product",
+}
+
+// knownProperties are property names defined by ogp.me, and of extendedTypes.
+var knownProperties = map[string]bool{
+	// Basic Metadata
+	"og:title": true,
+	"og:type":  true,
+	"og:image": true,
+	"og:url":   true,
+	// Optional Metadata
+	"og:audio":            true,
+	"og:description":      true,
+	"og:determiner":       true,
+	"og:locale":           true,
+	"og:locale:alternate": true,
+	"og:site_name":        true,
+	"og:video":            true,
+	// Structured Properties
+	"og:image:url":        true,
+	"og:image:secure_url": true,
+	"og:image:type":       true,
+	"og:image:width":      true,
+	"og:image:height":     true,
+	"og:image:alt":        true,
+	"og:video:url":        true,
+	"og:video:secure_url": true,
+	"og:video:type":       true,
+	"og:video:width":      true,
+	"og:video:height":     true,
+	"og:audio:url":        true,
+	"og:audio:secure_url": true,
+	"og:audio:type":       true,
+	// Facebook extensions
+	"og:image:user_generated":            true,
+	"og:restrictions:age":                true,
+	"og:restrictions:country:allowed":    true,
+	"og:restrictions:country:disallowed": true,
+	"og:restrictions:content":            true,
+	"og:ttl":                             true,
+	// Music
+	"music:duration":     true,
+	"music:album":        true,
+	"music:album:disc":   true,
+	"music:album:track":  true,
+	"music:musician":     true,
+	"music:song":         true,
+	"music:song:disc":    true,
+	"music:song:track":   true,
+	"music:release_date": true,
+	"music:creator":      true,
+	// Video
+	"video:actor":        true,
+	"video:actor:role":   true,
+	"video:director":     true,
+	"video:writer":       true,
+	"video:duration":     true,
+	"video:release_date": true,
+	"video:tag":          true,
+	"video:series":       true,
+	// Article
+	"article:published_time":  true,
+	"article:modified_time":   true,
+	"article:expiration_time": true,
+	"article:author":          true,
+	"article:section":         true,
+	"article:tag":             true,
+	// Book
+	"book:author":       true,
+	"book:isbn":         true,
+	"book:release_date": true,
+	"book:tag":          true,
+	// Product of extendedTypes
+	"product:price:amount":     true,
+	"product:price:currency":   true,
+	"product:availability":     true,
+	"product:condition":        true,
+	"product:brand":            true,
+	"product:retailer_item_id": true,
+	// Profile
+	"profile:first_name": true,
+	"profile:last_name":  true,
+	"profile:username":   true,
+	"profile:gender":     true,
+}
+
+// KnownTypes returns og:type values defined by ogp.me.
+// The returned slice is a copy, so modifying it doesn't affect the package.
+func KnownTypes() []string {
+	return append([]string{}, knownTypes...)
+}
+
+// KnownProperties returns the set of property names defined by ogp.me, and of product such as product:price:amount.
+// The returned map is a copy, so modifying it doesn't affect the package.
+func KnownProperties() map[string]bool {
+	props := make(map[string]bool, len(knownProperties))
+	for p := range knownProperties {
+		props[p] = true
+	}
+	return props
+}
+
+// Type represents "og:type" value such as "article" or "video.movie".
+type Type string
+
+// ParseType returns given og:type as Type, or an error if it's neither defined by ogp.me,
+// widely used such as "product", nor a custom type with namespace such as "myapp:recipe".
+func ParseType(s string) (Type, error) {
+	t := Type(strings.TrimSpace(s))
+	if !t.Valid() {
+		return "", fmt.Errorf("og:type is unknown: %s", s)
+	}
+	return t, nil
+}
+
+// Valid returns if t is defined by ogp.me, widely used such as "product", or a custom type with namespace.
+func (t Type) Valid() bool {
+	if IsKnownType(string(t)) || strings.Contains(string(t), ":") {
+		return true
+	}
+	for _, extended := range extendedTypes {
+		if string(t) == extended {
+			return true
+		}
+	}
+	return false
+}
+
+func (t Type) String() string {
+	return string(t)
+}
+
+// IsKnownType returns if given og:type is defined by ogp.me.
+func IsKnownType(t string) bool {
+	for _, known := range knownTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// IsWebsite returns if og:type is "website", which is also the default when og:type is empty.
+func (og *OpenGraph) IsWebsite() bool {
+	return og.typeNamespace() == "website"
+}
+
+// IsArticle returns if og:type is "article".
+func (og *OpenGraph) IsArticle() bool {
+	return og.typeNamespace() == "article"
+}
+
+// IsVideo returns if og:type is "video.*", e.g. "video.movie".
+func (og *OpenGraph) IsVideo() bool {
+	return og.typeNamespace() == "video"
+}
+
+// IsMusic returns if og:type is "music.*", e.g. "music.song".
+func (og *OpenGraph) IsMusic() bool {
+	return og.typeNamespace() == "music"
+}
+
+// IsProfile returns if og:type is "profile".
+func (og *OpenGraph) IsProfile() bool {
+	return og.typeNamespace() == "profile"
+}
+
+// IsProduct returns if og:type is "product" or "product.*", e.g. "product.item".
+func (og *OpenGraph) IsProduct() bool {
+	return og.typeNamespace() == "product"
+}
+
+// typeNamespace returns og:type before ".", or "website" if og:type is empty.
+func (og *OpenGraph) typeNamespace() string {
+	if og.Type == "" {
+		return "website"
+	}
+	t := string(og.Type)
+	if i := strings.Index(t, "."); i >= 0 {
+		return t[:i]
+	}
+	return t
+}
@@ -0,0 +1,81 @@
+package opengraph
+
+import "fmt"
+
+// Warning represents an advisory found while parsing.
+// Warnings never change parsed informations.
+type Warning struct {
+	Property string
+	Message  string
+	// First and Second are the values of a duplicated property, empty for other warnings.
+	First  string
+	Second string
+	// FirstIn and SecondIn are "head" or "body" where First and Second are declared,
+	// only for a duplicated scalar property such as og:title.
+	FirstIn  string
+	SecondIn string
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("%s: %s", w.Property, w.Message)
+}
+
+// warn records a Warning only if Policy.CollectWarnings is set.
+func (og *OpenGraph) warn(property, format string, args ...interface{}) {
+	if !og.Policy.CollectWarnings {
+		return
+	}
+	og.Warnings = append(og.Warnings, Warning{Property: property, Message: fmt.Sprintf(format, args...)})
+}
+
+// warnDuplicate records a Warning of a property declared twice, only if Policy.CollectWarnings is set.
+func (og *OpenGraph) warnDuplicate(property, first, second string) {
+	if !og.Policy.CollectWarnings {
+		return
+	}
+	og.Warnings = append(og.Warnings, Warning{
+		Property: property,
+		Message:  fmt.Sprintf("declared twice: %q and %q", first, second),
+		First:    first,
+		Second:   second,
+	})
+}
+
+// warnConflict records a warnDuplicate of a scalar property with where each value is declared.
+func (og *OpenGraph) warnConflict(property, first, second string, firstInBody, secondInBody bool) {
+	og.warnDuplicate(property, first, second)
+	if !og.Policy.CollectWarnings {
+		return
+	}
+	w := &og.Warnings[len(og.Warnings)-1]
+	w.FirstIn, w.SecondIn = section(firstInBody), section(secondInBody)
+}
+
+func section(inBody bool) string {
+	if inBody {
+		return "body"
+	}
+	return "head"
+}
+
+// warnIncompleteImages warns images without og:image:width, og:image:height or og:image:type.
+func (og *OpenGraph) warnIncompleteImages() {
+	if !og.Policy.CollectWarnings {
+		return
+	}
+	for _, img := range og.Image {
+		missing := []string{}
+		if img.Width == 0 {
+			missing = append(missing, "og:image:width")
+		}
+		if img.Height == 0 {
+			missing = append(missing, "og:image:height")
+		}
+		if img.Type == "" {
+			missing = append(missing, "og:image:type")
+		}
+		if len(missing) != 0 {
+			og.warn("og:image", "%s lacks %v", img.URL, missing)
+		}
+	}
+}
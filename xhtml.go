@@ -0,0 +1,62 @@
+package opengraph
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+
+	"golang.org/x/net/html"
+)
+
+// xmlProlog starts XML documents such as XHTML, `<?xml version="1.0"?>`.
+const xmlProlog = "<?xml"
+
+// voidElements are HTML elements which never have contents, and self-closing them is fine for HTML.
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "keygen": true, "link": true, "meta": true, "param": true, "source": true,
+	"track": true, "wbr": true,
+}
+
+// isXHTML reports if the document should be parsed as XHTML, i.e. served as application/xhtml+xml
+// or starting with XML prolog. It returns a reader of the whole body which the peeked bytes are left in.
+func (og *OpenGraph) isXHTML(body io.Reader) (io.Reader, bool) {
+	r := bufio.NewReader(body)
+	if og.xhtml {
+		return r, true
+	}
+	head, _ := r.Peek(len(xmlProlog))
+	return r, string(head) == xmlProlog
+}
+
+// expandSelfClosing rewrites self-closing tags of non-void elements in XHTML, such as
+// <script src="..."/> and <title/>, into pairs of start and end tags. Otherwise the HTML parser
+// regards them as start tags, and the rest of the document would be taken as their contents.
+func expandSelfClosing(body io.Reader) (io.Reader, error) {
+	buf := new(bytes.Buffer)
+	z := html.NewTokenizer(body)
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			if err := z.Err(); err != io.EOF {
+				return nil, err
+			}
+			return buf, nil
+		case html.SelfClosingTagToken:
+			// The tokenizer switches to raw text after <script/> and so on, expecting their end tags.
+			z.NextIsNotRawText()
+			// Token unescapes attributes in place, so Raw has to be copied before it.
+			raw := append([]byte{}, z.Raw()...)
+			t := z.Token()
+			if voidElements[t.Data] {
+				buf.Write(raw)
+				continue
+			}
+			t.Type = html.StartTagToken
+			buf.WriteString(t.String())
+			buf.WriteString("</" + t.Data + ">")
+		default:
+			buf.Write(z.Raw())
+		}
+	}
+}